@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rayque.oliveira/coverage-report-generator/pkg/coverage"
+)
+
+// Variáveis de ambiente: COVERAGE_REPORT_CONFIG indica o arquivo de
+// configuração e COVERAGE_REPORT_<FLAG> (ex.: COVERAGE_REPORT_MIN,
+// COVERAGE_REPORT_BADGE_LABEL) define o valor de uma flag. Flags que
+// aceitam vários valores usam vírgulas.
+const (
+	envPrefix = "COVERAGE_REPORT_"
+	envConfig = envPrefix + "CONFIG"
+)
+
+// exclusiveFlags são grupos de flags que formam uma única opção (ex.: o
+// modo de validação). Um valor de uma fonte de maior precedência descarta
+// os outros membros do grupo vindos de fontes menores: -lenient na linha de
+// comando substitui validation: strict do arquivo.
+var exclusiveFlags = [][]string{{"strict", "lenient"}}
+
+// envName retorna a variável de ambiente de uma flag
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// applyConfig completa as flags não informadas na linha de comando com as
+// variáveis de ambiente e, na falta delas, com o arquivo de configuração.
+// A precedência é flags > ambiente > arquivo.
+func applyConfig(flags *flag.FlagSet, opts *options) error {
+	path := opts.config
+	if path == "" {
+		path = os.Getenv(envConfig)
+	}
+	if path == "" {
+		path = findConfigFile()
+	}
+
+	values := map[string][]string{}
+	if path != "" {
+		config, err := coverage.LoadConfig(path)
+		if err != nil {
+			return err
+		}
+		values = configValues(config)
+		opts.rules = &config.Thresholds
+		opts.buckets = config.Buckets
+	}
+
+	explicit := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	// precedence indica de qual fonte vem o valor de uma flag: 3 linha de
+	// comando, 2 ambiente, 1 arquivo e 0 nenhuma
+	hasEnv := func(name string) bool {
+		_, ok := os.LookupEnv(envName(name))
+		return ok
+	}
+	precedence := func(name string) int {
+		switch {
+		case explicit[name]:
+			return 3
+		case hasEnv(name):
+			return 2
+		case len(values[name]) > 0:
+			return 1
+		}
+		return 0
+	}
+	overridden := func(name string) bool {
+		for _, group := range exclusiveFlags {
+			if !slices.Contains(group, name) {
+				continue
+			}
+			for _, other := range group {
+				if other != name && precedence(other) > precedence(name) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	var err error
+	flags.VisitAll(func(f *flag.Flag) {
+		if err != nil || explicit[f.Name] || f.Name == "config" || overridden(f.Name) {
+			return
+		}
+
+		source := path
+		flagValues := values[f.Name]
+		if env, ok := os.LookupEnv(envName(f.Name)); ok {
+			source = envName(f.Name)
+			flagValues = []string{env}
+			if _, isList := f.Value.(*stringList); isList {
+				flagValues = strings.Split(env, ",")
+			}
+		}
+
+		for _, value := range flagValues {
+			if setErr := flags.Set(f.Name, value); setErr != nil {
+				err = opts.lang().Errorf("%s: valor inválido para -%s: %w", source, f.Name, setErr)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// findConfigFile procura um dos arquivos padrão no diretório atual
+func findConfigFile() string {
+	for _, name := range coverage.DefaultConfigFiles {
+		if _, err := os.Stat(name); !errors.Is(err, fs.ErrNotExist) {
+			return name
+		}
+	}
+	return ""
+}
+
+// configValues converte a configuração nos valores das flags
+// correspondentes; campos vazios são omitidos
+func configValues(config *coverage.Config) map[string][]string {
+	values := map[string][]string{}
+	setString := func(name, value string) {
+		if value != "" {
+			values[name] = []string{value}
+		}
+	}
+	setList := func(name string, list []string) {
+		if len(list) > 0 {
+			values[name] = list
+		}
+	}
+	setFloat := func(name string, value float64) {
+		if value != 0 {
+			values[name] = []string{strconv.FormatFloat(value, 'g', -1, 64)}
+		}
+	}
+	setBool := func(name string, value bool) {
+		if value {
+			values[name] = []string{"true"}
+		}
+	}
+
+	setString("title", config.Title)
+	setString("lang", string(config.Language))
+	setString("templates", config.Templates)
+	setString("src", config.Source)
+	setList("in", config.Inputs)
+	setList("coverdir", config.CoverDirs)
+	setString("base", config.Base)
+	setBool("strict", config.Validation == "strict")
+	setBool("lenient", config.Validation == "lenient")
+
+	setList("include", config.Filters.Include)
+	setList("exclude", config.Filters.Exclude)
+	setList("include-regexp", config.Filters.IncludeRegexp)
+	setList("exclude-regexp", config.Filters.ExcludeRegexp)
+	setBool("keep-generated", config.Filters.KeepGenerated)
+	setBool("keep-ignored", config.Filters.KeepIgnored)
+
+	setFloat("min", config.Thresholds.Total)
+	setFloat("patch-min", config.PatchMin)
+
+	setString("out", config.Outputs.HTML)
+	setString("site", config.Outputs.Site)
+	setString("json", config.Outputs.JSON)
+	setString("md", config.Outputs.Markdown)
+	setString("diff-md", config.Outputs.DiffMarkdown)
+	setString("cobertura", config.Outputs.Cobertura)
+	setString("lcov", config.Outputs.LCOV)
+	setString("badge", config.Outputs.Badge)
+	setString("badge-label", config.Badge.Label)
+	if config.Badge.Precision != 0 {
+		values["badge-precision"] = []string{strconv.Itoa(config.Badge.Precision)}
+	}
+	return values
+}
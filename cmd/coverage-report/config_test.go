@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunConfig(t *testing.T) {
+	dir := t.TempDir()
+	profile := filepath.Join(dir, "coverage.out")
+	if err := os.WriteFile(profile, []byte(sampleProfile+"pkg/api.pb.go:1.1,2.2 5 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "report.html")
+	config := filepath.Join(dir, "coverage.yml")
+	content := "title: Relatório do <app>\n" +
+		"language: es\n" +
+		"inputs: [" + profile + "]\n" +
+		"filters:\n  exclude: ['*.pb.go']\n" +
+		"thresholds:\n  total: 60\n  files:\n    - pattern: file2.go\n      min: 10\n" +
+		"outputs:\n  html: " + report + "\n" +
+		"buckets:\n  - name: alta\n    label: Alta\n    min: 10\n    color: \"#00ff00\"\n  - name: baixa\n    min: 0\n    color: \"#ff0000\"\n"
+	if err := os.WriteFile(config, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		want     int
+		wantText string
+	}{
+		{"arquivo de configuração", []string{"-config", config}, nil, exitThreshold, "cobertura total 50,0% por debajo del mínimo de 60,0%"},
+		{"ambiente sobre o arquivo", []string{"-config", config}, map[string]string{"COVERAGE_REPORT_MIN": "40", "COVERAGE_REPORT_EXCLUDE": "*.pb.go,*.pb.gw.go"}, exitThreshold, "archivo pkg/file2.go"},
+		{"flag sobre o ambiente", []string{"-config", config, "-min", "0", "-thresholds", writeRules(t, dir)}, map[string]string{"COVERAGE_REPORT_MIN": "90"}, exitOK, ""},
+		{"configuração pelo ambiente", nil, map[string]string{envConfig: config, "COVERAGE_REPORT_MIN": "0"}, exitThreshold, "archivo pkg/file2.go"},
+		{"flag de lista substitui o arquivo", []string{"-config", config, "-exclude", "nada", "-min", "0"}, nil, exitThreshold, "archivo pkg/file2.go"},
+		{"valor inválido no ambiente", []string{"-config", config}, map[string]string{"COVERAGE_REPORT_MIN": "muito"}, exitUsage, "COVERAGE_REPORT_MIN"},
+		{"arquivo inexistente", []string{"-config", filepath.Join(dir, "nope.yml")}, nil, exitUsage, "configuração"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			os.Remove(report)
+
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(""), &stdout, &stderr)
+			if code != tt.want {
+				t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, tt.want, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantText) {
+				t.Errorf("stderr não contém %q: %s", tt.wantText, stderr.String())
+			}
+			if tt.want == exitUsage {
+				return
+			}
+
+			html, err := os.ReadFile(report)
+			if err != nil {
+				t.Fatalf("relatório não gerado em outputs.html: %v", err)
+			}
+			if !strings.Contains(string(html), "<title>Relatório do &lt;app&gt;</title>") {
+				t.Error("título da configuração não aplicado")
+			}
+			if !strings.Contains(string(html), `<div class="coverage-badge coverage-alta" title="Alta">`) {
+				t.Error("faixas da configuração não aplicadas")
+			}
+			if !strings.Contains(string(html), `<html lang="es">`) {
+				t.Error("idioma da configuração não aplicado")
+			}
+		})
+	}
+}
+
+// writeRules escreve um arquivo de limites sem regras
+func writeRules(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, "thresholds.json")
+	if err := os.WriteFile(path, []byte(`{"total": 0}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunDefaultConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	if err := os.WriteFile("coverage.out", []byte(sampleProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(".coverage-report.json", []byte(`{"outputs": {"html": "-"}, "title": "Padrão"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run(nil, strings.NewReader(""), &stdout, &stderr); code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "<title>Padrão</title>") {
+		t.Error("configuração padrão não aplicada")
+	}
+
+	if err := os.WriteFile(".coverage-report.json", []byte(`{"outputs": {"pdf": "r.pdf"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stderr.Reset()
+	if code := run(nil, strings.NewReader(""), &stdout, &stderr); code != exitUsage {
+		t.Errorf("código de saída = %d, esperado %d para chave desconhecida", code, exitUsage)
+	}
+	if !strings.Contains(stderr.String(), `"pdf"`) {
+		t.Errorf("erro não cita a chave desconhecida: %s", stderr.String())
+	}
+}
+
+func TestRunConfigValidation(t *testing.T) {
+	dir := t.TempDir()
+	profile := filepath.Join(dir, "coverage.out")
+	if err := os.WriteFile(profile, []byte("mode: set\npkg/file1.go:1.1,2.2 1 1\npkg/file1.go:3.1,4.x 1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "coverage.yml")
+	if err := os.WriteFile(config, []byte("validation: strict\ninputs: ["+profile+"]\noutputs:\n  html: \"-\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		want     int
+		wantText string
+	}{
+		{"arquivo strict", []string{"-config", config}, nil, exitError, "linha 3, coluna 20"},
+		{"flag -lenient sobre o arquivo", []string{"-config", config, "-lenient"}, nil, exitOK, "linha 3, coluna 20"},
+		{"ambiente LENIENT sobre o arquivo", []string{"-config", config}, map[string]string{"COVERAGE_REPORT_LENIENT": "true"}, exitOK, "linha 3, coluna 20"},
+		{"flag -strict sobre o ambiente", []string{"-config", config, "-strict"}, map[string]string{"COVERAGE_REPORT_LENIENT": "true"}, exitError, "linha 3, coluna 20"},
+		{"strict e lenient na mesma fonte", []string{"-config", config}, map[string]string{"COVERAGE_REPORT_STRICT": "true", "COVERAGE_REPORT_LENIENT": "true"}, exitUsage, "mutuamente exclusivos"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(""), &stdout, &stderr)
+			if code != tt.want {
+				t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, tt.want, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantText) {
+				t.Errorf("stderr não contém %q: %s", tt.wantText, stderr.String())
+			}
+		})
+	}
+}
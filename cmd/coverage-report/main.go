@@ -0,0 +1,703 @@
+// Command coverage-report converte um arquivo de cobertura do Go
+// (coverage.out) em um relatório HTML interativo.
+//
+// Uso:
+//
+//	coverage-report [-config .coverage-report.yml]
+//	                [-in coverage.out]... [-coverdir dir]...
+//	                [-out coverage-report.html] [-src .] [-title título]
+//	                [-lang pt-BR] [-templates dir]
+//	                [-strict | -lenient]
+//	                [-include glob]... [-exclude glob]...
+//	                [-include-regexp re]... [-exclude-regexp re]...
+//	                [-keep-generated] [-keep-ignored]
+//	                [-min 80] [-thresholds thresholds.json]
+//	                [-base base.out [-diff-md diff.md]]
+//	                [-patch changes.diff [-patch-min 80]]
+//	                [-cobertura coverage.xml] [-lcov lcov.info] [-json coverage.json]
+//	                [-md coverage.md]
+//	                [-badge coverage.svg [-badge-label coverage] [-badge-precision 0]]
+//	                [-buckets 80,60,40]
+//	                [-site coverage-site]
+//
+// -in pode ser repetido para combinar vários perfis (ex.: shards de CI) em
+// um único relatório; os perfis são lidos em paralelo. Tracefiles LCOV
+// (ex.: do front-end) são detectados pelo conteúdo e podem ser combinados
+// com perfis do Go. -coverdir lê diretórios GOCOVERDIR gravados por
+// binários compilados com go build -cover (ex.: testes de integração), que
+// são combinados com os perfis de -in; sem -in explícito, apenas os
+// diretórios são lidos. Use "-" em -in para ler da entrada padrão e em -out
+// para escrever na saída padrão. O código-fonte exibido no relatório é lido do módulo em
+// -src (o go.mod desse diretório define o caminho do módulo), que também é
+// usado para a tabela de cobertura por função.
+//
+// -include, -exclude, -include-regexp e -exclude-regexp selecionam os
+// arquivos do relatório (ex.: -exclude '*.pb.go' -exclude '**/vendor/**').
+// Arquivos com o cabeçalho "// Code generated ... DO NOT EDIT." e código
+// marcado com //coverage:ignore em -src também são removidos, a menos que
+// -keep-generated ou -keep-ignored sejam informados. Os totais e os limites
+// consideram apenas o que restou após os filtros.
+//
+// -strict valida o modo, todos os campos numéricos e a ordem das faixas dos
+// perfis do Go, abortando no primeiro erro com a linha e a coluna. -lenient
+// aplica as mesmas validações, mas ignora as linhas inválidas e as lista
+// como avisos em stderr.
+//
+// As opções também podem vir de um arquivo de configuração YAML ou JSON
+// (-config, COVERAGE_REPORT_CONFIG ou .coverage-report.yml, .yaml ou .json
+// no diretório atual; ver coverage.Config) e de variáveis de ambiente
+// COVERAGE_REPORT_<FLAG> (ex.: COVERAGE_REPORT_MIN=80). As flags têm
+// precedência sobre o ambiente, que tem precedência sobre o arquivo.
+//
+// Com -min ou -thresholds o relatório é gerado normalmente e, se a cobertura
+// ficar abaixo de algum limite, as violações são listadas em stderr e o
+// comando termina com código 3.
+//
+// Com -base o relatório mostra a variação de cobertura de cada arquivo em
+// relação ao perfil base, e -diff-md escreve um resumo Markdown da
+// comparação para comentários de pull request.
+//
+// Com -patch (ex.: git diff origin/main | coverage-report -patch - ...) o
+// comando calcula a cobertura apenas das linhas alteradas, destaca essas
+// linhas no relatório e, com -patch-min, aplica um mínimo a elas.
+//
+// -cobertura e -lcov escrevem, além do HTML, o XML Cobertura consumido por
+// Jenkins e GitLab e o tracefile LCOV usado por genhtml, Codecov e IDEs.
+// -json escreve o relatório no formato JSON versionado (ver
+// pkg/coverage/schema), que também é aceito em -in e -base. -md escreve um
+// resumo Markdown da cobertura que cabe em um comentário do GitHub. -badge
+// escreve um badge SVG com a cobertura total, sem serviços externos.
+// -buckets altera os mínimos das faixas de cobertura que definem as cores do
+// HTML e do badge e os emojis do Markdown; rótulos e cores das faixas são
+// definidos em buckets no arquivo de configuração.
+//
+// -lang escolhe o idioma do HTML, do site, dos resumos Markdown, das
+// mensagens de erro dos perfis e das mensagens e do uso do próprio comando:
+// pt-BR (padrão), en ou es. O idioma também define os separadores decimal e
+// de milhares dos números.
+//
+// -templates lê templates *.html de um diretório para personalizar o HTML
+// único: cada {{define "bloco"}} substitui o bloco padrão de mesmo nome
+// (header, sidebar, fileview etc.; ver coverage.TemplateBlocks e
+// coverage.HTMLReport).
+//
+// Com -site o relatório é gerado como um site estático (uma página por
+// pacote e por arquivo) no diretório informado, o que é mais leve para
+// projetos grandes. Nesse caso o HTML único só é gerado se -out também for
+// informado.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rayque.oliveira/coverage-report-generator/pkg/coverage"
+)
+
+// Códigos de saída do CLI
+const (
+	exitOK    = 0 // relatório gerado com sucesso
+	exitError = 1 // falha ao ler, parsear ou gerar o relatório
+	exitUsage = 2 // flags ou argumentos inválidos
+
+	exitThreshold = 3 // cobertura abaixo dos limites configurados
+)
+
+// stdioName é o nome usado em -in/-out para indicar stdin/stdout
+const stdioName = "-"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// options agrupa as flags do CLI
+type options struct {
+	config   string // arquivo de configuração
+	in       stringList
+	coverDir stringList // diretórios GOCOVERDIR
+	out      string
+	src      string
+	title    string // título do relatório HTML
+
+	langCode string            // código do idioma (-lang)
+	language coverage.Language // idioma normalizado de -lang
+
+	templates string // diretório com templates HTML próprios
+
+	include       stringList // globs de arquivos incluídos
+	exclude       stringList // globs de arquivos excluídos
+	includeRegexp stringList // regex de arquivos incluídos
+	excludeRegexp stringList // regex de arquivos excluídos
+	keepGenerated bool       // mantém arquivos com cabeçalho de código gerado
+	keepIgnored   bool       // desconsidera comentários //coverage:ignore
+
+	strict  bool // valida os perfis e aborta no primeiro erro
+	lenient bool // valida os perfis e ignora as linhas inválidas
+
+	minCoverage    float64                  // cobertura total mínima (-min)
+	thresholdsFile string                   // arquivo JSON com regras de limite
+	thresholds     *coverage.ThresholdRules // regras lidas de -thresholds
+	rules          *coverage.ThresholdRules // regras do arquivo de configuração
+
+	base   string // perfil base para comparação
+	diffMD string // destino do resumo Markdown da comparação
+
+	patch    string  // diff unificado com as linhas alteradas
+	patchMin float64 // cobertura mínima das linhas alteradas
+
+	cobertura string // destino do XML Cobertura
+	lcov      string // destino do tracefile LCOV
+	json      string // destino do relatório JSON
+	markdown  string // destino do resumo Markdown
+
+	badge          string // destino do badge SVG
+	badgeLabel     string // rótulo do badge
+	badgePrecision int    // casas decimais do percentual no badge
+
+	bucketLimits string                   // mínimos das faixas de cobertura (-buckets)
+	buckets      coverage.CoverageBuckets // faixas do arquivo de configuração e de -buckets
+
+	site string // diretório do site estático
+}
+
+// lang retorna o idioma das mensagens do CLI. Enquanto as flags são lidas,
+// vale o -lang informado até o momento, ou DefaultLanguage se inválido.
+func (o *options) lang() coverage.Language {
+	if o.language != "" {
+		return o.language
+	}
+	language, err := coverage.ParseLanguage(o.langCode)
+	if err != nil {
+		return coverage.DefaultLanguage
+	}
+	return language
+}
+
+// stringList é uma flag que pode ser informada várias vezes
+type stringList []string
+
+func (sl *stringList) String() string {
+	return strings.Join(*sl, ",")
+}
+
+func (sl *stringList) Set(value string) error {
+	*sl = append(*sl, value)
+	return nil
+}
+
+// run executa o CLI e retorna o código de saída
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	opts, err := parseFlags(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	report, err := generate(opts, stdin, stdout, stderr)
+	if err != nil {
+		fmt.Fprintf(stderr, "coverage-report: %v\n", err)
+		return exitError
+	}
+
+	if opts.out != "" && opts.out != stdioName {
+		fmt.Fprintln(stderr, opts.language.Sprintf("✅ Relatório gerado: %s", opts.out))
+	}
+	if opts.site != "" {
+		fmt.Fprintln(stderr, opts.language.Sprintf("✅ Site gerado: %s", filepath.Join(opts.site, "index.html")))
+	}
+
+	if report.patch != nil {
+		report.patch.WriteLocalizedSummary(stderr, opts.language)
+	}
+
+	violations, err := checkThresholds(opts, report)
+	if err != nil {
+		fmt.Fprintf(stderr, "coverage-report: %v\n", err)
+		return exitError
+	}
+	if len(violations) > 0 {
+		coverage.WriteViolations(stderr, violations, opts.language)
+		return exitThreshold
+	}
+	return exitOK
+}
+
+func parseFlags(args []string, stderr io.Writer) (*options, error) {
+	opts := &options{}
+
+	fs := flag.NewFlagSet("coverage-report", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.config, "config", "", "arquivo de configuração YAML ou JSON (padrão .coverage-report.yml, .yaml ou .json, se existir)")
+	fs.Var(&opts.in, "in", `arquivo de cobertura de entrada ("-" para stdin); pode ser repetido (padrão "coverage.out")`)
+	fs.Var(&opts.coverDir, "coverdir", "diretório GOCOVERDIR de binários compilados com go build -cover; pode ser repetido")
+	fs.StringVar(&opts.out, "out", "coverage-report.html", `arquivo HTML de saída ("-" para stdout)`)
+	fs.StringVar(&opts.src, "src", ".", "diretório raiz do módulo com o código-fonte")
+	fs.StringVar(&opts.title, "title", "", "título do relatório HTML")
+	fs.StringVar(&opts.templates, "templates", "", "diretório com templates *.html que redefinem blocos do HTML (header, sidebar, fileview...)")
+	fs.StringVar(&opts.langCode, "lang", "", "idioma dos relatórios e das mensagens: pt-BR, en ou es (padrão pt-BR)")
+	fs.Var(&opts.include, "include", "inclui apenas arquivos que casam com o glob (*, ?, **); pode ser repetido")
+	fs.Var(&opts.exclude, "exclude", `exclui arquivos que casam com o glob (ex.: "*.pb.go", "**/vendor/**"); pode ser repetido`)
+	fs.Var(&opts.includeRegexp, "include-regexp", "inclui apenas arquivos que casam com a expressão regular; pode ser repetido")
+	fs.Var(&opts.excludeRegexp, "exclude-regexp", "exclui arquivos que casam com a expressão regular; pode ser repetido")
+	fs.BoolVar(&opts.keepGenerated, "keep-generated", false, `mantém arquivos com o cabeçalho "Code generated ... DO NOT EDIT."`)
+	fs.BoolVar(&opts.keepIgnored, "keep-ignored", false, "desconsidera os comentários //coverage:ignore")
+	fs.BoolVar(&opts.strict, "strict", false, "valida modo, campos numéricos e faixas dos perfis, abortando no primeiro erro")
+	fs.BoolVar(&opts.lenient, "lenient", false, "valida os perfis como -strict, mas ignora as linhas inválidas e mostra avisos")
+	fs.Float64Var(&opts.minCoverage, "min", 0, "cobertura total mínima em %; abaixo dela o comando sai com código 3")
+	fs.StringVar(&opts.thresholdsFile, "thresholds", "", "arquivo JSON com limites total, por pacote e por arquivo")
+	fs.StringVar(&opts.base, "base", "", "perfil de cobertura base para comparar com -in (ex.: da branch principal)")
+	fs.StringVar(&opts.diffMD, "diff-md", "", `escreve o resumo Markdown da comparação com -base neste arquivo ("-" para stdout)`)
+	fs.StringVar(&opts.patch, "patch", "", `diff unificado (ex.: git diff) para calcular a cobertura das linhas alteradas ("-" para stdin)`)
+	fs.Float64Var(&opts.patchMin, "patch-min", 0, "cobertura mínima em % das linhas alteradas; abaixo dela o comando sai com código 3")
+	fs.StringVar(&opts.cobertura, "cobertura", "", `escreve também o relatório no formato XML Cobertura neste arquivo ("-" para stdout)`)
+	fs.StringVar(&opts.lcov, "lcov", "", `escreve também o tracefile LCOV neste arquivo ("-" para stdout)`)
+	fs.StringVar(&opts.json, "json", "", `escreve também o relatório no formato JSON versionado neste arquivo ("-" para stdout)`)
+	fs.StringVar(&opts.markdown, "md", "", `escreve também um resumo Markdown para comentários de pull request neste arquivo ("-" para stdout)`)
+	fs.StringVar(&opts.badge, "badge", "", `escreve também um badge SVG com a cobertura total neste arquivo ("-" para stdout)`)
+	fs.StringVar(&opts.badgeLabel, "badge-label", "coverage", "rótulo do badge SVG")
+	fs.IntVar(&opts.badgePrecision, "badge-precision", 0, "casas decimais do percentual no badge SVG (0 a 4)")
+	fs.StringVar(&opts.bucketLimits, "buckets", "", "mínimos das faixas de cobertura das cores, do maior para o menor, separados por vírgula (padrão 80,60,40)")
+	fs.StringVar(&opts.site, "site", "", "gera o relatório como site estático (uma página por pacote e por arquivo) neste diretório")
+
+	// As descrições das flags são traduzidas ao exibir o uso, quando -lang
+	// já pode ter sido lido
+	usages := map[string]string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		usages[f.Name] = f.Usage
+	})
+	fs.Usage = func() {
+		lang := opts.lang()
+		fmt.Fprintf(stderr, "%s\n\n%s\n", lang.Text("Uso: coverage-report [opções]"), lang.Text("Opções:"))
+		fs.VisitAll(func(f *flag.Flag) {
+			f.Usage = lang.Text(usages[f.Name])
+		})
+		fs.PrintDefaults()
+	}
+
+	// usageError mostra o erro no idioma de -lang, seguido do uso
+	usageError := func(format string, args ...any) error {
+		err := opts.lang().Errorf(format, args...)
+		fmt.Fprintf(stderr, "coverage-report: %v\n", err)
+		fs.Usage()
+		return err
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if fs.NArg() > 0 {
+		return nil, usageError("argumento inesperado: %s", fs.Arg(0))
+	}
+
+	if err := applyConfig(fs, opts); err != nil {
+		fmt.Fprintf(stderr, "coverage-report: %v\n", err)
+		return nil, err
+	}
+
+	if len(opts.in) == 0 && len(opts.coverDir) == 0 {
+		opts.in = stringList{"coverage.out"}
+	}
+
+	if slices.Contains(opts.in, "") || opts.out == "" {
+		return nil, usageError("-in e -out não podem ser vazios")
+	}
+
+	if slices.Contains(opts.coverDir, "") || slices.Contains(opts.coverDir, stdioName) {
+		return nil, usageError("-coverdir deve ser um diretório")
+	}
+
+	if opts.strict && opts.lenient {
+		return nil, usageError("-strict e -lenient são mutuamente exclusivos")
+	}
+
+	if opts.site == stdioName {
+		return nil, usageError("-site deve ser um diretório")
+	}
+
+	// Com -site o HTML único é opcional
+	outSet := false
+	fs.Visit(func(f *flag.Flag) {
+		outSet = outSet || f.Name == "out"
+	})
+	if opts.site != "" && !outSet {
+		opts.out = ""
+	}
+
+	if opts.diffMD != "" && opts.base == "" {
+		return nil, usageError("-diff-md requer -base")
+	}
+
+	outputs := []string{opts.out, opts.diffMD, opts.cobertura, opts.lcov, opts.json, opts.markdown, opts.badge}
+	if i := slices.Index(outputs, stdioName); i >= 0 && slices.Contains(outputs[i+1:], stdioName) {
+		return nil, usageError("apenas uma saída pode usar stdout")
+	}
+
+	if opts.minCoverage < 0 || opts.minCoverage > 100 || opts.patchMin < 0 || opts.patchMin > 100 {
+		return nil, usageError("-min e -patch-min devem estar entre 0 e 100")
+	}
+
+	if opts.patchMin > 0 && opts.patch == "" {
+		return nil, usageError("-patch-min requer -patch")
+	}
+
+	if opts.badgePrecision < 0 || opts.badgePrecision > 4 {
+		return nil, usageError("-badge-precision deve estar entre 0 e 4")
+	}
+
+	language, err := coverage.ParseLanguage(opts.langCode)
+	if err != nil {
+		return nil, usageError("-lang: %w", err)
+	}
+	opts.language = language
+
+	if opts.bucketLimits != "" {
+		var limits []float64
+		for _, field := range strings.Split(opts.bucketLimits, ",") {
+			limit, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, usageError("-buckets: limite inválido %q", field)
+			}
+			limits = append(limits, limit)
+		}
+		buckets, err := opts.buckets.WithLimits(limits)
+		if err != nil {
+			return nil, usageError("-buckets: %w", err)
+		}
+		opts.buckets = buckets
+	}
+
+	// As regras são validadas antes de gerar qualquer saída
+	if opts.thresholdsFile != "" {
+		rules, err := loadThresholds(opts.thresholdsFile, opts.language)
+		if err != nil {
+			return nil, usageError("-thresholds: %w", err)
+		}
+		opts.thresholds = rules
+	}
+
+	inputs := append(slices.Clone(opts.in), opts.base, opts.patch)
+	if i := slices.Index(inputs, stdioName); i >= 0 && slices.Contains(inputs[i+1:], stdioName) {
+		return nil, usageError("stdin só pode ser lido uma vez")
+	}
+
+	return opts, nil
+}
+
+// report reúne os resultados calculados para o relatório
+type report struct {
+	coverage *coverage.ProjectCoverage
+	patch    *coverage.PatchCoverage // nil sem -patch
+}
+
+// generate lê os arquivos de cobertura, escreve o relatório HTML e retorna
+// os resultados calculados
+func generate(opts *options, stdin io.Reader, stdout, stderr io.Writer) (*report, error) {
+	cov, err := readProfiles(opts, stdin, stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := coverage.NewSourceResolver(opts.src)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := coverage.FilterOptions{
+		Include:       opts.include,
+		Exclude:       opts.exclude,
+		IncludeRegexp: opts.includeRegexp,
+		ExcludeRegexp: opts.excludeRegexp,
+		Sources:       sources,
+		KeepGenerated: opts.keepGenerated,
+		KeepIgnored:   opts.keepIgnored,
+		OnWarning: func(filePath string, err error) {
+			fmt.Fprintln(stderr, opts.language.Sprintf("⚠️  filtros do código-fonte não aplicados: %v", err))
+		},
+	}
+	if cov, err = coverage.FilterCoverage(cov, filter); err != nil {
+		return nil, err
+	}
+
+	htmlOpts := coverage.HTMLOptions{
+		Title:    opts.title,
+		Sources:  sources,
+		Buckets:  opts.buckets,
+		Language: opts.language,
+	}
+	if opts.templates != "" {
+		htmlOpts.Templates = os.DirFS(opts.templates)
+	}
+
+	functions, err := coverage.AnalyzeFunctions(cov, sources)
+	if err != nil {
+		// Código-fonte divergente do perfil não impede o relatório: os
+		// arquivos com erro ficam fora da tabela de funções
+		errs := []error{err}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			errs = joined.Unwrap()
+		}
+		for _, err := range errs {
+			fmt.Fprintln(stderr, opts.language.Sprintf("⚠️  cobertura por função indisponível: %v", err))
+		}
+	}
+	htmlOpts.Functions = functions
+
+	if opts.base != "" {
+		base, err := readCoverage(opts.base, opts, stdin, stderr)
+		if err != nil {
+			return nil, err
+		}
+		if base, err = coverage.FilterCoverage(base, filter); err != nil {
+			return nil, err
+		}
+
+		htmlOpts.Diff = coverage.CompareCoverage(base, cov)
+		if opts.diffMD != "" {
+			err := writeOutput(opts.diffMD, stdout, opts.language, func(w io.Writer) error {
+				return htmlOpts.Diff.WriteLocalizedMarkdown(w, opts.language)
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.patch != "" {
+		changes, err := readPatch(opts.patch, stdin, opts.language)
+		if err != nil {
+			return nil, err
+		}
+		htmlOpts.Patch = coverage.ComputePatchCoverage(cov, changes)
+	}
+
+	if opts.cobertura != "" {
+		cobertura := coverage.NewCoberturaGenerator(cov, coverage.CoberturaOptions{
+			Sources:   sources,
+			Functions: functions,
+		})
+		if err := writeOutput(opts.cobertura, stdout, opts.language, cobertura.Generate); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.lcov != "" {
+		lcov := coverage.NewLCOVGenerator(cov, coverage.LCOVOptions{
+			Sources:   sources,
+			Functions: functions,
+		})
+		if err := writeOutput(opts.lcov, stdout, opts.language, lcov.Generate); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.json != "" {
+		json := coverage.NewJSONGenerator(cov, coverage.JSONOptions{
+			Functions: functions,
+			Indent:    true,
+		})
+		if err := writeOutput(opts.json, stdout, opts.language, json.Generate); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.markdown != "" {
+		markdown := coverage.NewMarkdownGenerator(cov, coverage.MarkdownOptions{
+			Buckets:  opts.buckets,
+			Language: opts.language,
+		})
+		if err := writeOutput(opts.markdown, stdout, opts.language, markdown.Generate); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.badge != "" {
+		badge := coverage.NewBadgeGenerator(cov, coverage.BadgeOptions{
+			Label:     opts.badgeLabel,
+			Precision: opts.badgePrecision,
+			Buckets:   opts.buckets,
+		})
+		if err := writeOutput(opts.badge, stdout, opts.language, badge.Generate); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.site != "" {
+		if err := coverage.NewSiteGenerator(cov, htmlOpts).Generate(opts.site); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.out != "" {
+		generator := coverage.NewHTMLGeneratorWithOptions(cov, htmlOpts)
+		err = writeOutput(opts.out, stdout, opts.language, func(w io.Writer) error {
+			return generator.Generate(w)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &report{coverage: cov, patch: htmlOpts.Patch}, nil
+}
+
+// readPatch lê o diff unificado de um arquivo ou de stdin
+func readPatch(path string, stdin io.Reader, lang coverage.Language) (coverage.ChangedLines, error) {
+	reader := stdin
+	if path != stdioName {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, lang.Errorf("erro ao abrir diff: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	changes, err := coverage.ParseUnifiedDiffWithOptions(reader, coverage.ParseOptions{Language: lang})
+	if err != nil {
+		return nil, lang.Errorf("erro ao ler diff %s: %w", path, err)
+	}
+	return changes, nil
+}
+
+// readProfiles lê em paralelo e combina todos os perfis informados em -in
+// com os diretórios de -coverdir
+func readProfiles(opts *options, stdin io.Reader, stderr io.Writer) (*coverage.ProjectCoverage, error) {
+	sources := make([]coverage.ProfileSource, 0, len(opts.in))
+	for _, path := range opts.in {
+		name, reader, closeInput, err := openInput(path, stdin, opts.language)
+		if err != nil {
+			return nil, err
+		}
+		defer closeInput()
+		sources = append(sources, coverage.ProfileSource{Name: name, Reader: reader})
+	}
+
+	cov, err := coverage.LoadProfiles(context.Background(), sources, coverage.LoadOptions{
+		ParseOptions: coverage.ParseOptions{Language: opts.language},
+		Parse:        parseAnyFormat(opts),
+		OnWarning: func(source string, warning *coverage.ParseError) {
+			fmt.Fprintf(stderr, "⚠️  %s: %v\n", source, warning)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range opts.coverDir {
+		dirCov, err := coverage.ReadCoverDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		if err := cov.Merge(dirCov); err != nil {
+			return nil, opts.lang().Errorf("erro ao combinar %s: %w", dir, err)
+		}
+	}
+	return cov, nil
+}
+
+// readCoverage lê um único perfil (ex.: -base) em qualquer formato aceito
+// por parseAnyFormat
+func readCoverage(path string, opts *options, stdin io.Reader, stderr io.Writer) (*coverage.ProjectCoverage, error) {
+	name, reader, closeInput, err := openInput(path, stdin, opts.language)
+	if err != nil {
+		return nil, err
+	}
+	defer closeInput()
+
+	cov, warnings, err := parseAnyFormat(opts)(reader)
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "⚠️  %s: %v\n", name, warning)
+	}
+	if err != nil {
+		return nil, opts.lang().Errorf("erro ao parsear %s: %w", name, err)
+	}
+	return cov, nil
+}
+
+// openInput abre um arquivo de cobertura ou stdin
+func openInput(path string, stdin io.Reader, lang coverage.Language) (string, io.Reader, func() error, error) {
+	if path == stdioName {
+		return "stdin", stdin, func() error { return nil }, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", nil, nil, lang.Errorf("erro ao abrir arquivo de cobertura: %w", err)
+	}
+	return path, file, file.Close, nil
+}
+
+// parseAnyFormat retorna um parser que aceita perfis do Go, tracefiles LCOV
+// e relatórios JSON, detectando o formato pelo conteúdo. -strict e -lenient
+// se aplicam aos perfis do Go.
+func parseAnyFormat(opts *options) func(io.Reader) (*coverage.ProjectCoverage, []*coverage.ParseError, error) {
+	parseOpts := coverage.ParseOptions{Language: opts.language}
+	switch {
+	case opts.strict:
+		parseOpts.Validation = coverage.ValidateStrict
+	case opts.lenient:
+		parseOpts.Validation = coverage.ValidateLenient
+	}
+
+	return func(reader io.Reader) (*coverage.ProjectCoverage, []*coverage.ParseError, error) {
+		buffered := bufio.NewReader(reader)
+		switch {
+		case isLCOV(buffered):
+			cov, err := coverage.ParseLCOVWithOptions(buffered, parseOpts)
+			return cov, nil, err
+		case isJSON(buffered):
+			cov, err := coverage.ParseJSONReportWithOptions(buffered, parseOpts)
+			return cov, nil, err
+		default:
+			return coverage.ParseCoverageFileWithOptions(buffered, parseOpts)
+		}
+	}
+}
+
+// isLCOV indica se o conteúdo começa com um registro LCOV (TN: ou SF:)
+func isLCOV(reader *bufio.Reader) bool {
+	head, _ := reader.Peek(512)
+	text := strings.TrimLeft(string(head), " \t\r\n")
+	return strings.HasPrefix(text, "TN:") || strings.HasPrefix(text, "SF:")
+}
+
+// isJSON indica se o conteúdo começa com um objeto JSON
+func isJSON(reader *bufio.Reader) bool {
+	head, _ := reader.Peek(512)
+	return strings.HasPrefix(strings.TrimLeft(string(head), " \t\r\n"), "{")
+}
+
+// writeOutput abre o destino (arquivo ou stdout) e delega a escrita.
+// Em caso de falha o arquivo parcial é removido.
+func writeOutput(path string, stdout io.Writer, lang coverage.Language, write func(io.Writer) error) error {
+	if path == stdioName {
+		return write(stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return lang.Errorf("erro ao criar arquivo de saída: %w", err)
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return lang.Errorf("erro ao gerar relatório: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return lang.Errorf("erro ao fechar arquivo de saída: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/rayque.oliveira/coverage-report-generator/pkg/coverage"
+)
+
+const sampleProfile = `mode: atomic
+pkg/file1.go:1.1,2.2 1 1
+pkg/file2.go:3.1,4.2 1 0
+`
+
+func TestRunStdinToStdout(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"-in", "-", "-out", "-"}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "<!DOCTYPE html>") {
+		t.Error("stdout não contém HTML")
+	}
+	if !strings.Contains(stdout.String(), "file1.go") {
+		t.Error("stdout não contém file1.go")
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "coverage.out")
+	out := filepath.Join(dir, "report.html")
+
+	if err := os.WriteFile(in, []byte(sampleProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", in, "-out", out}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("relatório não gerado: %v", err)
+	}
+	if !strings.Contains(string(data), "file2.go") {
+		t.Error("relatório não contém file2.go")
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  int
+	}{
+		{"flag desconhecida", []string{"-foo"}, "", exitUsage},
+		{"argumento extra", []string{"extra"}, "", exitUsage},
+		{"entrada vazia", []string{"-in", ""}, "", exitUsage},
+		{"stdin repetido", []string{"-in", "-", "-in", "-"}, "", exitUsage},
+		{"arquivo inexistente", []string{"-in", filepath.Join(dir, "nope.out"), "-out", "-"}, "", exitError},
+		{"stdin vazio", []string{"-in", "-", "-out", "-"}, "", exitError},
+		{"perfil inválido", []string{"-in", "-", "-out", "-"}, "mode: set\nlixo\n", exitError},
+		{"diff sem base", []string{"-diff-md", "diff.md"}, "", exitUsage},
+		{"diff e relatório em stdout", []string{"-base", "a.out", "-diff-md", "-", "-out", "-"}, "", exitUsage},
+		{"cobertura e relatório em stdout", []string{"-cobertura", "-", "-out", "-"}, "", exitUsage},
+		{"patch-min sem patch", []string{"-patch-min", "80"}, "", exitUsage},
+		{"strict e lenient", []string{"-strict", "-lenient"}, "", exitUsage},
+		{"perfil inválido no modo estrito", []string{"-strict", "-in", "-", "-out", "-"}, "mode: set\npkg/a.go:2.1,1.1 1 1\n", exitError},
+		{"site em stdout", []string{"-site", "-"}, "", exitUsage},
+		{"badge-precision inválida", []string{"-badge", "b.svg", "-badge-precision", "5"}, "", exitUsage},
+		{"buckets com limites a menos", []string{"-buckets", "90,75"}, "", exitUsage},
+		{"buckets com limite inválido", []string{"-buckets", "90,alto,50"}, "", exitUsage},
+		{"buckets fora de ordem", []string{"-buckets", "50,75,90"}, "", exitUsage},
+		{"idioma não suportado", []string{"-lang", "fr"}, "", exitUsage},
+		{"patch e perfil em stdin", []string{"-in", "-", "-patch", "-"}, "", exitUsage},
+		{"regex de exclusão inválida", []string{"-in", "-", "-out", "-", "-exclude-regexp", "("}, sampleProfile, exitError},
+		{"coverdir em stdin", []string{"-coverdir", "-"}, "", exitUsage},
+		{"coverdir sem covmeta", []string{"-coverdir", dir, "-out", "-"}, "", exitError},
+		{"ajuda", []string{"-h"}, "", exitOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if code != tt.want {
+				t.Errorf("código de saída = %d, esperado %d (stderr: %s)", code, tt.want, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRunRemovesPartialOutputOnError(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "report.html")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", out}, strings.NewReader(""), &stdout, &stderr)
+	if code != exitError {
+		t.Fatalf("código de saída = %d, esperado %d", code, exitError)
+	}
+
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("arquivo de saída não deveria existir após erro de parse")
+	}
+}
+
+func TestRunMergesMultipleProfiles(t *testing.T) {
+	dir := t.TempDir()
+	shardA := filepath.Join(dir, "a.out")
+	shardB := filepath.Join(dir, "b.out")
+
+	if err := os.WriteFile(shardA, []byte("mode: set\npkg/file1.go:1.1,2.2 1 0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(shardB, []byte("mode: set\npkg/file1.go:1.1,2.2 1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", shardA, "-in", shardB, "-out", "-"}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Cobertura Total: 100,0%") {
+		t.Error("perfis não foram combinados")
+	}
+}
+
+func TestRunRejectsMixedModes(t *testing.T) {
+	dir := t.TempDir()
+	shard := filepath.Join(dir, "a.out")
+	if err := os.WriteFile(shard, []byte("mode: count\npkg/file1.go:1.1,2.2 1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", shard, "-in", "-", "-out", "-"}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitError {
+		t.Fatalf("código de saída = %d, esperado %d", code, exitError)
+	}
+	if !strings.Contains(stderr.String(), "incompatíveis") {
+		t.Errorf("mensagem de erro inesperada: %s", stderr.String())
+	}
+}
+
+func TestRunWithBase(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.out")
+	diffMD := filepath.Join(dir, "diff.md")
+
+	if err := os.WriteFile(base, []byte("mode: atomic\npkg/file1.go:1.1,2.2 1 1\npkg/file2.go:3.1,4.2 1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", "-", "-base", base, "-diff-md", diffMD}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "Variação vs. Base") {
+		t.Error("HTML não contém a variação em relação à base")
+	}
+
+	md, err := os.ReadFile(diffMD)
+	if err != nil {
+		t.Fatalf("resumo Markdown não gerado: %v", err)
+	}
+	if !strings.Contains(string(md), "(-50,0 pp)") {
+		t.Errorf("resumo Markdown inesperado:\n%s", md)
+	}
+}
+
+func TestRunCobertura(t *testing.T) {
+	dir := t.TempDir()
+	xmlOut := filepath.Join(dir, "coverage.xml")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", "-", "-cobertura", xmlOut}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	data, err := os.ReadFile(xmlOut)
+	if err != nil {
+		t.Fatalf("XML Cobertura não gerado: %v", err)
+	}
+	if !strings.Contains(string(data), `<class name="file1" filename="pkg/file1.go"`) {
+		t.Errorf("XML Cobertura inesperado:\n%s", data)
+	}
+}
+
+func TestRunLCOV(t *testing.T) {
+	dir := t.TempDir()
+	frontend := filepath.Join(dir, "frontend.info")
+	lcovOut := filepath.Join(dir, "merged.info")
+
+	if err := os.WriteFile(frontend, []byte("TN:\nSF:web/app.ts\nDA:1,1\nend_of_record\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-in", frontend, "-out", "-", "-lcov", lcovOut}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	data, err := os.ReadFile(lcovOut)
+	if err != nil {
+		t.Fatalf("LCOV não gerado: %v", err)
+	}
+	for _, want := range []string{"SF:pkg/file1.go\n", "SF:web/app.ts\nDA:1,1\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("LCOV não contém %q:\n%s", want, data)
+		}
+	}
+	if !strings.Contains(stdout.String(), "app.ts") {
+		t.Error("HTML não contém o arquivo do LCOV")
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	dir := t.TempDir()
+	jsonOut := filepath.Join(dir, "coverage.json")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", filepath.Join(dir, "report.html"), "-json", jsonOut}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	data, err := os.ReadFile(jsonOut)
+	if err != nil {
+		t.Fatalf("JSON não gerado: %v", err)
+	}
+	if !strings.Contains(string(data), `"schemaVersion": 1`) {
+		t.Errorf("JSON sem versão de schema:\n%s", data)
+	}
+
+	// O JSON gerado pode ser usado de volta como entrada
+	stdout.Reset()
+	code = run([]string{"-in", jsonOut, "-out", "-"}, nil, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d ao ler JSON (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "file1.go") {
+		t.Error("HTML gerado a partir do JSON não contém os arquivos do perfil")
+	}
+}
+
+func TestRunMarkdown(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", filepath.Join(dir, "report.html"), "-md", "-"}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "## ") || !strings.Contains(stdout.String(), "<details>") {
+		t.Errorf("resumo Markdown inesperado:\n%s", stdout.String())
+	}
+}
+
+func TestRunBadge(t *testing.T) {
+	dir := t.TempDir()
+	badge := filepath.Join(dir, "coverage.svg")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-in", "-", "-out", filepath.Join(dir, "report.html"), "-badge", badge, "-badge-label", "cobertura", "-badge-precision", "1"}
+	code := run(args, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	data, err := os.ReadFile(badge)
+	if err != nil {
+		t.Fatalf("badge não gerado: %v", err)
+	}
+	if !regexp.MustCompile(`aria-label="cobertura: \d+\.\d%"`).Match(data) {
+		t.Errorf("badge inesperado:\n%s", data)
+	}
+}
+
+func TestRunBuckets(t *testing.T) {
+	dir := t.TempDir()
+	badge := filepath.Join(dir, "coverage.svg")
+
+	// 50% fica na faixa fair com os limites padrão e na poor com 95,90,60
+	var stdout, stderr bytes.Buffer
+	args := []string{"-in", "-", "-out", "-", "-badge", badge, "-buckets", "95, 90, 60"}
+	code := run(args, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `<div class="coverage-badge coverage-poor"`) {
+		t.Error("HTML não usa a faixa dos limites de -buckets")
+	}
+
+	data, err := os.ReadFile(badge)
+	if err != nil {
+		t.Fatalf("badge não gerado: %v", err)
+	}
+	if !strings.Contains(string(data), coverage.DefaultCoverageBuckets[3].Color) {
+		t.Errorf("badge não usa a cor da faixa poor:\n%s", data)
+	}
+}
+
+func TestRunLanguage(t *testing.T) {
+	dir := t.TempDir()
+	markdown := filepath.Join(dir, "coverage.md")
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-in", "-", "-out", "-", "-md", markdown, "-lang", "en-US"}
+	code := run(args, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	for _, want := range []string{`<html lang="en">`, "Total Coverage: 50.0%"} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("HTML não contém %q", want)
+		}
+	}
+
+	data, err := os.ReadFile(markdown)
+	if err != nil {
+		t.Fatalf("resumo Markdown não gerado: %v", err)
+	}
+	if !strings.Contains(string(data), "### Files with the lowest coverage") {
+		t.Errorf("resumo Markdown não traduzido:\n%s", data)
+	}
+
+	stderr.Reset()
+	code = run([]string{"-in", "-", "-out", "-", "-strict", "-lang", "es"}, strings.NewReader("mode: set\npkg/a.go:1.1,2.2 x 1\n"), &stdout, &stderr)
+	if code != exitError {
+		t.Fatalf("código de saída = %d, esperado %d", code, exitError)
+	}
+	if !strings.Contains(stderr.String(), "error al analizar stdin: línea 2, columna 18: número no válido") {
+		t.Errorf("erro de parse não traduzido: %s", stderr.String())
+	}
+}
+
+func TestRunLanguageMessages(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "report.html")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", out, "-lang", "en"}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if want := "✅ Report generated: " + out; !strings.Contains(stderr.String(), want) {
+		t.Errorf("stderr não contém %q: %s", want, stderr.String())
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{
+			[]string{"-lang", "en", "-min", "200"},
+			[]string{"coverage-report: -min and -patch-min must be between 0 and 100", "Usage: coverage-report [options]", "minimum total coverage in %"},
+		},
+		{
+			[]string{"-lang", "es", "extra"},
+			[]string{"coverage-report: argumento inesperado: extra", "Opciones:", "título del informe HTML"},
+		},
+		{
+			[]string{"-min", "200"},
+			[]string{"-min e -patch-min devem estar entre 0 e 100", "Uso: coverage-report [opções]"},
+		},
+	}
+	for _, tt := range tests {
+		stderr.Reset()
+		if code := run(tt.args, strings.NewReader(""), &stdout, &stderr); code != exitUsage {
+			t.Fatalf("%v: código de saída = %d, esperado %d", tt.args, code, exitUsage)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(stderr.String(), want) {
+				t.Errorf("%v: stderr não contém %q:\n%s", tt.args, want, stderr.String())
+			}
+		}
+	}
+}
+
+func TestRunTemplates(t *testing.T) {
+	dir := t.TempDir()
+	header := `{{define "header"}}<header class="tema">{{.Title}}: {{percent .Total.Percent}}</header>{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "header.html"), []byte(header), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", "-", "-templates", dir}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `<header class="tema">Relatório de Cobertura de Testes: 50,0%</header>`) {
+		t.Error("HTML não usa o template de -templates")
+	}
+	if !strings.Contains(stdout.String(), `id="fileList"`) {
+		t.Error("HTML não contém os blocos padrão não redefinidos")
+	}
+
+	stderr.Reset()
+	code = run([]string{"-in", "-", "-out", "-", "-templates", filepath.Join(dir, "nada")}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitError {
+		t.Errorf("código de saída = %d, esperado %d para diretório inexistente", code, exitError)
+	}
+}
+
+func TestRunSite(t *testing.T) {
+	dir := t.TempDir()
+	site := filepath.Join(dir, "site")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-site", site}, strings.NewReader(sampleProfile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	for _, page := range []string{"index.html", "assets/style.css", "assets/site.js", "packages/pkg/index.html", "files/pkg/file1.go.html"} {
+		if _, err := os.Stat(filepath.Join(site, filepath.FromSlash(page))); err != nil {
+			t.Errorf("página %s não gerada: %v", page, err)
+		}
+	}
+
+	// Sem -out explícito o HTML único não é gerado
+	if _, err := os.Stat("coverage-report.html"); err == nil {
+		t.Error("HTML único gerado sem -out")
+	}
+	if !strings.Contains(stderr.String(), "Site gerado") {
+		t.Errorf("stderr inesperado: %s", stderr.String())
+	}
+}
+
+func TestRunCoverDir(t *testing.T) {
+	coverDir := filepath.Join("..", "..", "pkg", "coverage", "testdata", "coverdir")
+	profile := "mode: count\nexample.com/demo/calc/calc.go:5.3,6.1 1 4\n"
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-coverdir", coverDir, "-out", filepath.Join(t.TempDir(), "report.html"), "-json", "-"}, strings.NewReader(profile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	report, err := coverage.ParseJSONReport(&stdout)
+	if err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	file := report.Files["example.com/demo/calc/calc.go"]
+	if file == nil || file.CoveredStmt != 3 || file.TotalStmt != 6 {
+		t.Errorf("calc.go = %+v, esperado 3/6 statements", file)
+	}
+	if report.Files["example.com/demo/main.go"] == nil {
+		t.Error("main.go do GOCOVERDIR ausente")
+	}
+}
+
+func TestRunFilters(t *testing.T) {
+	profile := sampleProfile + "pkg/api.pb.go:1.1,2.2 5 0\nvendor/lib/lib.go:1.1,2.2 5 0\n"
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-in", "-", "-out", filepath.Join(t.TempDir(), "report.html"), "-json", "-",
+		"-exclude", "*.pb.go", "-exclude-regexp", "^vendor/", "-min", "50"}, strings.NewReader(profile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+
+	report, err := coverage.ParseJSONReport(&stdout)
+	if err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	if len(report.Files) != 2 || report.GetTotalCoverage() != 50 {
+		t.Errorf("arquivos = %d, cobertura = %.1f; esperado 2 e 50%%", len(report.Files), report.GetTotalCoverage())
+	}
+}
+
+func TestRunLenient(t *testing.T) {
+	profile := "mode: set\npkg/file1.go:1.1,2.2 1 1\npkg/file1.go:3.1,4.x 1 1\n"
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-lenient", "-in", "-", "-out", "-"}, strings.NewReader(profile), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, exitOK, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "stdin: linha 3, coluna 20") {
+		t.Errorf("aviso não reportado em stderr: %s", stderr.String())
+	}
+}
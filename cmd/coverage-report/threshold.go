@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rayque.oliveira/coverage-report-generator/pkg/coverage"
+)
+
+// loadThresholds lê e valida o arquivo de -thresholds
+func loadThresholds(path string, lang coverage.Language) (*coverage.ThresholdRules, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, lang.Errorf("erro ao abrir arquivo de limites: %w", err)
+	}
+	defer file.Close()
+
+	rules, err := coverage.LoadThresholdRules(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// checkThresholds aplica -thresholds, -min e -patch-min ao relatório. Sem
+// nenhuma dessas flags nenhuma verificação é feita.
+func checkThresholds(opts *options, r *report) ([]coverage.ThresholdViolation, error) {
+	rules := &coverage.ThresholdRules{}
+	switch {
+	case opts.thresholds != nil:
+		// -thresholds substitui as regras do arquivo de configuração
+		copied := *opts.thresholds
+		rules = &copied
+	case opts.rules != nil:
+		copied := *opts.rules
+		copied.Total = 0 // o total do arquivo de configuração já está em -min
+		rules = &copied
+	}
+
+	// -min tem precedência sobre o total do arquivo de regras
+	if opts.minCoverage > 0 {
+		rules.Total = opts.minCoverage
+	}
+
+	violations, err := coverage.CheckThresholds(r.coverage, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.patch != nil {
+		violations = append(violations, r.patch.CheckThreshold(opts.patchMin)...)
+	}
+	return violations, nil
+}
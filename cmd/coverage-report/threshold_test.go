@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunThresholds(t *testing.T) {
+	dir := t.TempDir()
+	rulesFile := filepath.Join(dir, "thresholds.json")
+	rules := `{"total": 40, "files": [{"pattern": "file2.go", "min": 30}]}`
+	if err := os.WriteFile(rulesFile, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     int
+		wantText string
+	}{
+		{"sem limites", nil, exitOK, ""},
+		{"mínimo atingido", []string{"-min", "50"}, exitOK, ""},
+		{"mínimo não atingido", []string{"-min", "75"}, exitThreshold, "cobertura total 50,0% abaixo do mínimo de 75,0%"},
+		{"arquivo de regras", []string{"-thresholds", rulesFile}, exitThreshold, "arquivo pkg/file2.go"},
+		{"arquivo de regras inexistente", []string{"-thresholds", filepath.Join(dir, "nope.json")}, exitUsage, "arquivo de limites"},
+		{"mínimo inválido", []string{"-min", "101"}, exitUsage, "-min"},
+		{"mínimo em inglês", []string{"-min", "75", "-lang", "en"}, exitThreshold, "❌ Coverage below the minimum (1 violation):\n  - total coverage 50.0% below the minimum of 75.0%"},
+		{"regras em espanhol", []string{"-thresholds", rulesFile, "-lang", "es"}, exitThreshold, "archivo pkg/file2.go: 0,0% por debajo del mínimo de 30,0% (regla \"file2.go\")"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-in", "-", "-out", "-"}, tt.args...)
+
+			code := run(args, strings.NewReader(sampleProfile), &stdout, &stderr)
+			if code != tt.want {
+				t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, tt.want, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantText) {
+				t.Errorf("stderr não contém %q: %s", tt.wantText, stderr.String())
+			}
+		})
+	}
+}
+
+// TestRunInvalidThresholds verifica que regras inválidas são rejeitadas
+// antes de qualquer relatório ser escrito
+func TestRunInvalidThresholds(t *testing.T) {
+	dir := t.TempDir()
+	rulesFile := filepath.Join(dir, "thresholds.json")
+	if err := os.WriteFile(rulesFile, []byte(`{"total": 120}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "report.html")
+	jsonOut := filepath.Join(dir, "report.json")
+
+	for _, rules := range []string{rulesFile, filepath.Join(dir, "nope.json")} {
+		var stdout, stderr bytes.Buffer
+		args := []string{"-in", "-", "-out", out, "-json", jsonOut, "-thresholds", rules}
+
+		code := run(args, strings.NewReader(sampleProfile), &stdout, &stderr)
+		if code != exitUsage {
+			t.Fatalf("%s: código de saída = %d, esperado %d (stderr: %s)", rules, code, exitUsage, stderr.String())
+		}
+		if !strings.Contains(stderr.String(), "-thresholds: ") {
+			t.Errorf("%s: stderr sem o erro de -thresholds: %s", rules, stderr.String())
+		}
+		for _, path := range []string{out, jsonOut} {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("%s: %s não deveria ter sido gerado", rules, path)
+			}
+		}
+	}
+}
+
+func TestRunPatchCoverage(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "coverage.out")
+	if err := os.WriteFile(in, []byte(sampleProfile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	patch := "--- a/pkg/file2.go\n+++ b/pkg/file2.go\n@@ -3,0 +3,2 @@\n+a\n+b\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     int
+		wantText string
+	}{
+		{"resumo do patch", nil, exitOK, "Cobertura do patch: 0,0% (0/1 statements)"},
+		{"mínimo do patch", []string{"-patch-min", "80"}, exitThreshold, "cobertura do patch 0,0% abaixo do mínimo de 80,0%"},
+		{"resumo do patch em inglês", []string{"-lang", "en"}, exitOK, "Patch coverage: 0.0% (0/1 statements)\n  pkg/file2.go: 0.0% (0/1) — uncovered lines: 3"},
+		{"mínimo do patch em espanhol", []string{"-patch-min", "80", "-lang", "es"}, exitThreshold, "cobertura del parche 0,0% por debajo del mínimo de 80,0%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-in", in, "-out", "-", "-patch", "-"}, tt.args...)
+
+			code := run(args, strings.NewReader(patch), &stdout, &stderr)
+			if code != tt.want {
+				t.Fatalf("código de saída = %d, esperado %d (stderr: %s)", code, tt.want, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantText) {
+				t.Errorf("stderr não contém %q: %s", tt.wantText, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), `"changed":[3,4]`) {
+				t.Error("HTML não destaca as linhas alteradas")
+			}
+		})
+	}
+}
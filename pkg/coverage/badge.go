@@ -0,0 +1,107 @@
+package coverage
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strings"
+)
+
+// Valores padrão e limites do BadgeGenerator
+const (
+	defaultBadgeLabel = "coverage"
+	maxBadgePrecision = 4
+)
+
+// BadgeOptions configura o BadgeGenerator
+type BadgeOptions struct {
+	// Label é o texto da parte esquerda do badge (padrão "coverage")
+	Label string `json:"label,omitempty"`
+	// Precision é o número de casas decimais do percentual (0 a 4)
+	Precision int `json:"precision,omitempty"`
+	// Buckets define a cor do badge pela faixa de cobertura (padrão
+	// DefaultCoverageBuckets); na configuração, vem de Config.Buckets
+	Buckets CoverageBuckets `json:"-"`
+}
+
+// BadgeGenerator gera um badge SVG no estilo shields.io com a cobertura
+// total, sem depender de serviços externos
+type BadgeGenerator struct {
+	coverage *ProjectCoverage
+	opts     BadgeOptions
+}
+
+// NewBadgeGenerator cria um gerador de badge
+func NewBadgeGenerator(coverage *ProjectCoverage, opts BadgeOptions) *BadgeGenerator {
+	if opts.Label == "" {
+		opts.Label = defaultBadgeLabel
+	}
+	return &BadgeGenerator{coverage: coverage, opts: opts}
+}
+
+// Generate escreve o badge SVG no writer
+func (bg *BadgeGenerator) Generate(w io.Writer) error {
+	if bg.opts.Precision < 0 || bg.opts.Precision > maxBadgePrecision {
+		return fmt.Errorf("precisão do badge deve estar entre 0 e %d", maxBadgePrecision)
+	}
+	if err := bg.opts.Buckets.Validate(); err != nil {
+		return err
+	}
+
+	// A cor segue o valor exibido, para que um badge "80%" nunca fique na
+	// faixa inferior por causa do arredondamento
+	total := roundFloat(bg.coverage.GetTotalCoverage(), bg.opts.Precision)
+	value := fmt.Sprintf("%.*f%%", bg.opts.Precision, total)
+	color := bg.opts.Buckets.Find(total).Color
+
+	label := html.EscapeString(bg.opts.Label)
+	labelWidth := badgeTextWidth(bg.opts.Label) + 10
+	valueWidth := badgeTextWidth(value) + 10
+	width := labelWidth + valueWidth
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`+"\n", width, label, value)
+	fmt.Fprintf(&sb, "  <title>%s: %s</title>\n", label, value)
+	sb.WriteString(`  <linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&sb, `  <clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", width)
+	sb.WriteString(`  <g clip-path="url(#r)">` + "\n")
+	fmt.Fprintf(&sb, `    <rect width="%d" height="20" fill="#555"/>`+"\n", labelWidth)
+	fmt.Fprintf(&sb, `    <rect x="%d" width="%d" height="20" fill="%s"/>`+"\n", labelWidth, valueWidth, color)
+	fmt.Fprintf(&sb, `    <rect width="%d" height="20" fill="url(#s)"/>`+"\n", width)
+	sb.WriteString("  </g>\n")
+	sb.WriteString(`  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	writeBadgeText(&sb, float64(labelWidth)/2, label)
+	writeBadgeText(&sb, float64(labelWidth)+float64(valueWidth)/2, value)
+	sb.WriteString("  </g>\n")
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeBadgeText escreve o texto com a sombra usada pelos badges shields.io
+func writeBadgeText(sb *strings.Builder, x float64, text string) {
+	fmt.Fprintf(sb, `    <text x="%.1f" y="15" fill="#010101" fill-opacity=".3">%s</text>`+"\n", x, text)
+	fmt.Fprintf(sb, `    <text x="%.1f" y="14">%s</text>`+"\n", x, text)
+}
+
+// badgeTextWidth estima a largura em pixels do texto em Verdana 11px.
+// A estimativa por classe de caractere é suficiente para o badge, já que
+// o texto é centralizado em cada metade.
+func badgeTextWidth(text string) int {
+	width := 0.0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune("iljtfI.,:;!|' ", r):
+			width += 3.9
+		case strings.ContainsRune("mwMW%", r):
+			width += 10.5
+		case r >= 'A' && r <= 'Z':
+			width += 7.5
+		default:
+			width += 7.0
+		}
+	}
+	return int(math.Ceil(width))
+}
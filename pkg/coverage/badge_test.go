@@ -0,0 +1,97 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBadgeGenerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		covered int
+		opts    BadgeOptions
+		value   string
+		color   string
+	}{
+		{"excelente", 850, BadgeOptions{}, "85%", DefaultCoverageBuckets[0].Color},
+		{"boa", 655, BadgeOptions{Precision: 1}, "65.5%", DefaultCoverageBuckets[1].Color},
+		{"regular", 400, BadgeOptions{Label: "cobertura"}, "40%", DefaultCoverageBuckets[2].Color},
+		{"baixa", 123, BadgeOptions{Precision: 2}, "12.30%", DefaultCoverageBuckets[3].Color},
+		// 79.96% arredonda para 80% e usa a faixa do valor exibido
+		{"arredondamento", 7996, BadgeOptions{}, "80%", DefaultCoverageBuckets[0].Color},
+		{"faixas personalizadas", 850, BadgeOptions{Buckets: orgBuckets}, "85%", orgBuckets[1].Color},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total := 1000
+			if tt.covered > total {
+				total = 10000
+			}
+			cov := mustParse(t, fmt.Sprintf("mode: set\nexample.com/p/a.go:1.1,2.1 %d 1\nexample.com/p/a.go:3.1,4.1 %d 0\n",
+				tt.covered, total-tt.covered))
+
+			var buf bytes.Buffer
+			if err := NewBadgeGenerator(cov, tt.opts).Generate(&buf); err != nil {
+				t.Fatalf("erro ao gerar badge: %v", err)
+			}
+			svg := buf.String()
+
+			label := tt.opts.Label
+			if label == "" {
+				label = "coverage"
+			}
+			for _, want := range []string{
+				fmt.Sprintf(`aria-label="%s: %s"`, label, tt.value),
+				fmt.Sprintf(`fill="%s"`, tt.color),
+				">" + tt.value + "</text>",
+			} {
+				if !strings.Contains(svg, want) {
+					t.Errorf("badge não contém %q:\n%s", want, svg)
+				}
+			}
+			assertWellFormedXML(t, svg)
+		})
+	}
+}
+
+func TestBadgeEscapesLabel(t *testing.T) {
+	cov := mustParse(t, "mode: set\nexample.com/p/a.go:1.1,2.1 1 1\n")
+
+	var buf bytes.Buffer
+	if err := NewBadgeGenerator(cov, BadgeOptions{Label: `<a & "b">`}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "<a &") {
+		t.Error("rótulo não escapado")
+	}
+	assertWellFormedXML(t, buf.String())
+}
+
+func TestBadgePrecisionRange(t *testing.T) {
+	cov := mustParse(t, "mode: set\nexample.com/p/a.go:1.1,2.1 1 1\n")
+	for _, precision := range []int{-1, maxBadgePrecision + 1} {
+		err := NewBadgeGenerator(cov, BadgeOptions{Precision: precision}).Generate(io.Discard)
+		if err == nil {
+			t.Errorf("esperado erro para precisão %d", precision)
+		}
+	}
+}
+
+func assertWellFormedXML(t *testing.T, doc string) {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("XML inválido: %v\n%s", err, doc)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package coverage
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CoverageBucket é uma faixa de cobertura. Um valor pertence à primeira
+// faixa, em ordem decrescente de Min, cujo limite ele atinge.
+type CoverageBucket struct {
+	// Name identifica a faixa e compõe a classe CSS coverage-<name>
+	// (letras minúsculas, dígitos e hífens)
+	Name string `json:"name"`
+	// Label é o nome exibido da faixa (padrão Name)
+	Label string `json:"label,omitempty"`
+	// Min é a cobertura mínima da faixa, em porcentagem
+	Min float64 `json:"min"`
+	// Color é a cor do badge SVG e da borda das etiquetas no HTML
+	Color string `json:"color"`
+	// Background é o fundo das etiquetas no HTML (padrão #ffffff)
+	Background string `json:"background,omitempty"`
+	// Text é a cor do texto das etiquetas no HTML (padrão Color)
+	Text string `json:"text,omitempty"`
+	// Emoji marca a faixa no Markdown (padrão ⚪)
+	Emoji string `json:"emoji,omitempty"`
+}
+
+// CoverageBuckets são as faixas usadas para colorir a cobertura, da maior
+// para a menor. Valores abaixo da última faixa também usam a última.
+type CoverageBuckets []CoverageBucket
+
+// DefaultCoverageBuckets são as faixas usadas quando nenhuma é configurada
+var DefaultCoverageBuckets = CoverageBuckets{
+	{Name: "excellent", Label: "Excelente", Min: 80, Color: "#2ea44f", Background: "#dcffe4", Text: "#0d643d", Emoji: "🟢"},
+	{Name: "good", Label: "Bom", Min: 60, Color: "#0366d6", Background: "#cce5ff", Text: "#0366d6", Emoji: "🟡"},
+	{Name: "fair", Label: "Regular", Min: 40, Color: "#dfb317", Background: "#fff8c5", Text: "#856404", Emoji: "🟠"},
+	{Name: "poor", Label: "Fraco", Min: 0, Color: "#e05d44", Background: "#ffeef0", Text: "#6f42c1", Emoji: "🔴"},
+}
+
+var (
+	bucketNamePattern  = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	bucketColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+	// reservedBucketNames colidiriam com outras classes coverage-* do relatório
+	reservedBucketNames = map[string]bool{"badge": true, "indicator": true, "report": true}
+)
+
+// Validate verifica se as faixas estão em ordem decrescente de Min, com
+// nomes únicos e cores no formato #rgb ou #rrggbb
+func (buckets CoverageBuckets) Validate() error {
+	names := map[string]bool{}
+	for i, bucket := range buckets {
+		if !bucketNamePattern.MatchString(bucket.Name) {
+			return fmt.Errorf("faixa %d: nome %q inválido (use letras minúsculas, dígitos e hífens)", i+1, bucket.Name)
+		}
+		if reservedBucketNames[bucket.Name] {
+			return fmt.Errorf("faixa %d: nome %q reservado", i+1, bucket.Name)
+		}
+		if names[bucket.Name] {
+			return fmt.Errorf("faixa %q repetida", bucket.Name)
+		}
+		names[bucket.Name] = true
+
+		if bucket.Min < 0 || bucket.Min > 100 {
+			return fmt.Errorf("faixa %q: mínimo deve estar entre 0 e 100, obtido %.1f", bucket.Name, bucket.Min)
+		}
+		if i > 0 && bucket.Min >= buckets[i-1].Min {
+			return fmt.Errorf("faixa %q: mínimo %.1f deve ser menor que o da faixa anterior (%.1f)", bucket.Name, bucket.Min, buckets[i-1].Min)
+		}
+
+		for _, color := range []struct{ field, value string }{
+			{"color", bucket.Color}, {"background", bucket.Background}, {"text", bucket.Text},
+		} {
+			if (color.value != "" || color.field == "color") && !bucketColorPattern.MatchString(color.value) {
+				return fmt.Errorf("faixa %q: %s %q inválida (use #rgb ou #rrggbb)", bucket.Name, color.field, color.value)
+			}
+		}
+	}
+	return nil
+}
+
+// WithLimits retorna uma cópia das faixas (ou de DefaultCoverageBuckets) com
+// os mínimos informados, em ordem decrescente. limits tem um valor para cada
+// faixa exceto a última, que passa a cobrir o restante (ex.: 90, 75, 50 para
+// as quatro faixas padrão).
+func (buckets CoverageBuckets) WithLimits(limits []float64) (CoverageBuckets, error) {
+	buckets = slices.Clone(buckets.orDefault())
+	if len(limits) != len(buckets)-1 {
+		return nil, fmt.Errorf("esperados %d limites para %d faixas, obtidos %d", len(buckets)-1, len(buckets), len(limits))
+	}
+	for i, limit := range limits {
+		buckets[i].Min = limit
+	}
+	buckets[len(buckets)-1].Min = 0
+	if err := buckets.Validate(); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// orDefault retorna as faixas configuradas ou DefaultCoverageBuckets
+func (buckets CoverageBuckets) orDefault() CoverageBuckets {
+	if len(buckets) == 0 {
+		return DefaultCoverageBuckets
+	}
+	return buckets
+}
+
+// Find retorna a faixa de um valor de cobertura. Sem faixas configuradas,
+// usa DefaultCoverageBuckets.
+func (buckets CoverageBuckets) Find(coverage float64) CoverageBucket {
+	buckets = buckets.orDefault()
+	for _, bucket := range buckets {
+		if coverage >= bucket.Min {
+			return bucket
+		}
+	}
+	return buckets[len(buckets)-1]
+}
+
+// class retorna a classe CSS da faixa
+func (bucket CoverageBucket) class() string {
+	return "coverage-" + bucket.Name
+}
+
+// label retorna o nome exibido da faixa
+func (bucket CoverageBucket) label() string {
+	if bucket.Label == "" {
+		return bucket.Name
+	}
+	return bucket.Label
+}
+
+// emoji retorna o emoji da faixa no Markdown
+func (bucket CoverageBucket) emoji() string {
+	if bucket.Emoji == "" {
+		return "⚪"
+	}
+	return bucket.Emoji
+}
+
+// css gera as regras de estilo de cada faixa
+func (buckets CoverageBuckets) css() string {
+	var sb strings.Builder
+	for _, bucket := range buckets.orDefault() {
+		background := bucket.Background
+		if background == "" {
+			background = "#ffffff"
+		}
+		text := bucket.Text
+		if text == "" {
+			text = bucket.Color
+		}
+		fmt.Fprintf(&sb, `
+    .%s {
+        background: %s;
+        color: %s;
+        border: 1px solid %s;
+    }
+`, bucket.class(), background, text, bucket.Color)
+	}
+	return sb.String()
+}
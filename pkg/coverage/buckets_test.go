@@ -0,0 +1,154 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// orgBuckets são faixas 90/75/50 com rótulos e cores próprios
+var orgBuckets = CoverageBuckets{
+	{Name: "a", Label: "Ótimo", Min: 90, Color: "#00aa00", Emoji: "✅"},
+	{Name: "b", Label: "Aceitável", Min: 75, Color: "#123456", Background: "#eeeeee", Text: "#000"},
+	{Name: "c", Min: 50, Color: "#ffaa00", Emoji: "⚠️"},
+	{Name: "d", Label: "Crítico", Min: 0, Color: "#cc0000", Emoji: "❌"},
+}
+
+func TestCoverageBucketsFind(t *testing.T) {
+	tests := []struct {
+		buckets  CoverageBuckets
+		coverage float64
+		want     string
+	}{
+		{nil, 80, "excellent"},
+		{nil, 79.9, "good"},
+		{nil, 40, "fair"},
+		{nil, 0, "poor"},
+		{orgBuckets, 100, "a"},
+		{orgBuckets, 85, "b"},
+		{orgBuckets, 75, "b"},
+		{orgBuckets, 50, "c"},
+		{orgBuckets, 10, "d"},
+		// Abaixo da última faixa usa a última
+		{CoverageBuckets{{Name: "alta", Min: 70, Color: "#0f0"}, {Name: "baixa", Min: 30, Color: "#f00"}}, 10, "baixa"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.buckets.Find(tt.coverage).Name; got != tt.want {
+			t.Errorf("Find(%.1f) = %q, esperado %q", tt.coverage, got, tt.want)
+		}
+	}
+}
+
+func TestCoverageBucketsValidate(t *testing.T) {
+	if err := DefaultCoverageBuckets.Validate(); err != nil {
+		t.Errorf("faixas padrão inválidas: %v", err)
+	}
+	if err := orgBuckets.Validate(); err != nil {
+		t.Errorf("faixas personalizadas inválidas: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		buckets CoverageBuckets
+		want    string
+	}{
+		{"nome vazio", CoverageBuckets{{Min: 50, Color: "#fff"}}, "nome"},
+		{"nome com espaço", CoverageBuckets{{Name: "muito bom", Color: "#fff"}}, "muito bom"},
+		{"nome reservado", CoverageBuckets{{Name: "badge", Color: "#fff"}}, "reservado"},
+		{"nome repetido", CoverageBuckets{{Name: "a", Min: 80, Color: "#fff"}, {Name: "a", Color: "#fff"}}, "repetida"},
+		{"fora de ordem", CoverageBuckets{{Name: "a", Min: 50, Color: "#fff"}, {Name: "b", Min: 80, Color: "#fff"}}, "menor"},
+		{"mínimo fora da faixa", CoverageBuckets{{Name: "a", Min: 120, Color: "#fff"}}, "120"},
+		{"sem cor", CoverageBuckets{{Name: "a"}}, "color"},
+		{"cor inválida", CoverageBuckets{{Name: "a", Color: "red; x: y"}}, "color"},
+		{"fundo inválido", CoverageBuckets{{Name: "a", Color: "#fff", Background: "url(x)"}}, "background"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.buckets.Validate()
+			if err == nil {
+				t.Fatal("esperava erro")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("erro = %v, esperado mencionar %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoverageBucketsWithLimits(t *testing.T) {
+	buckets, err := CoverageBuckets(nil).WithLimits([]float64{90, 75, 50})
+	if err != nil {
+		t.Fatalf("erro: %v", err)
+	}
+	for i, want := range []float64{90, 75, 50, 0} {
+		if buckets[i].Min != want || buckets[i].Name != DefaultCoverageBuckets[i].Name {
+			t.Errorf("faixa %d = %s/%.0f, esperado %s/%.0f", i, buckets[i].Name, buckets[i].Min, DefaultCoverageBuckets[i].Name, want)
+		}
+	}
+	if DefaultCoverageBuckets[0].Min != 80 {
+		t.Error("WithLimits alterou as faixas padrão")
+	}
+
+	if _, err := CoverageBuckets(nil).WithLimits([]float64{90, 75}); err == nil {
+		t.Error("esperava erro para quantidade errada de limites")
+	}
+	if _, err := CoverageBuckets(nil).WithLimits([]float64{50, 75, 90}); err == nil {
+		t.Error("esperava erro para limites fora de ordem")
+	}
+}
+
+func TestCustomBucketsOutputs(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/p/a.go:1.1,2.1 17 1
+example.com/p/a.go:3.1,4.1 3 0
+example.com/p/b.go:1.1,2.1 1 1
+example.com/p/b.go:3.1,4.1 1 0
+`)
+
+	var buf bytes.Buffer
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{Buckets: orgBuckets}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar HTML: %v", err)
+	}
+	report := buf.String()
+	for _, want := range []string{
+		// 18/22 = 81,8% fica na faixa b
+		`<div class="coverage-badge coverage-b" title="Aceitável">`,
+		"background: #eeeeee;\n        color: #000;\n        border: 1px solid #123456;",
+		// Sem Text e Background, usa Color sobre branco
+		"background: #ffffff;\n        color: #cc0000;",
+		`<span class="file-coverage-badge coverage-c" title="c">50%</span>`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("HTML não contém %q", want)
+		}
+	}
+	if strings.Contains(report, "coverage-excellent") {
+		t.Error("HTML contém classes das faixas padrão")
+	}
+
+	buf.Reset()
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{Buckets: orgBuckets}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar Markdown: %v", err)
+	}
+	for _, want := range []string{"## ⚪ Cobertura: 81,8%", "| ⚠️ | `example.com/p/b.go` |"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Markdown não contém %q:\n%s", want, buf.String())
+		}
+	}
+
+	invalid := CoverageBuckets{{Name: "x", Color: "vermelho"}}
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{Buckets: invalid}).Generate(&buf); err == nil {
+		t.Error("esperava erro do HTML para faixas inválidas")
+	}
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{Buckets: invalid}).Generate(&buf); err == nil {
+		t.Error("esperava erro do Markdown para faixas inválidas")
+	}
+	if err := NewBadgeGenerator(cov, BadgeOptions{Buckets: invalid}).Generate(&buf); err == nil {
+		t.Error("esperava erro do badge para faixas inválidas")
+	}
+	if err := NewSiteGenerator(cov, HTMLOptions{Buckets: invalid}).Generate(t.TempDir()); err == nil {
+		t.Error("esperava erro do site para faixas inválidas")
+	}
+}
@@ -0,0 +1,237 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// coberturaDoctype referencia o DTD oficial do formato Cobertura
+const coberturaDoctype = `<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`
+
+// CoberturaOptions configura o CoberturaGenerator
+type CoberturaOptions struct {
+	// Sources, quando informado, torna os nomes de arquivo relativos à raiz
+	// do módulo e a publica em <sources>, como esperam GitLab e Jenkins
+	Sources *SourceResolver
+	// Functions adiciona os métodos de cada classe (ver AnalyzeFunctions)
+	Functions []FunctionCoverage
+	// Timestamp do relatório; quando zero, usa o horário atual
+	Timestamp time.Time
+}
+
+// CoberturaGenerator converte a cobertura para o formato XML do Cobertura.
+// Cada pacote Go vira um <package> e cada arquivo um <class>; os blocos são
+// expandidos em linhas com o maior contador entre os blocos da linha.
+type CoberturaGenerator struct {
+	coverage *ProjectCoverage
+	opts     CoberturaOptions
+}
+
+// NewCoberturaGenerator cria um gerador de XML Cobertura
+func NewCoberturaGenerator(coverage *ProjectCoverage, opts CoberturaOptions) *CoberturaGenerator {
+	return &CoberturaGenerator{coverage: coverage, opts: opts}
+}
+
+type coberturaCoverage struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        string             `xml:"line-rate,attr"`
+	BranchRate      string             `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      string             `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       int64              `xml:"timestamp,attr"`
+	Sources         *coberturaSources  `xml:"sources,omitempty"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaSources struct {
+	Source []string `xml:"source"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   string           `xml:"line-rate,attr"`
+	BranchRate string           `xml:"branch-rate,attr"`
+	Complexity string           `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string            `xml:"name,attr"`
+	Filename   string            `xml:"filename,attr"`
+	LineRate   string            `xml:"line-rate,attr"`
+	BranchRate string            `xml:"branch-rate,attr"`
+	Complexity string            `xml:"complexity,attr"`
+	Methods    []coberturaMethod `xml:"methods>method"`
+	Lines      []coberturaLine   `xml:"lines>line"`
+}
+
+type coberturaMethod struct {
+	Name       string          `xml:"name,attr"`
+	Signature  string          `xml:"signature,attr"`
+	LineRate   string          `xml:"line-rate,attr"`
+	BranchRate string          `xml:"branch-rate,attr"`
+	Complexity string          `xml:"complexity,attr"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int    `xml:"number,attr"`
+	Hits   int    `xml:"hits,attr"`
+	Branch string `xml:"branch,attr"`
+}
+
+// Generate escreve o XML Cobertura no writer
+func (cg *CoberturaGenerator) Generate(w io.Writer) error {
+	timestamp := cg.opts.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	doc := coberturaCoverage{
+		BranchRate: "0",
+		Complexity: "0",
+		Version:    "coverage-report-generator",
+		Timestamp:  timestamp.UnixMilli(),
+		Packages:   []coberturaPackage{},
+	}
+
+	if cg.opts.Sources != nil {
+		doc.Sources = &coberturaSources{Source: []string{cg.opts.Sources.Root}}
+	}
+
+	functionsByFile := make(map[string][]FunctionCoverage)
+	for _, fn := range cg.opts.Functions {
+		functionsByFile[fn.FilePath] = append(functionsByFile[fn.FilePath], fn)
+	}
+
+	totalLines, totalCovered := 0, 0
+	for _, pkg := range cg.coverage.GetPackages() {
+		xmlPkg := coberturaPackage{
+			Name:       trimModulePath(cg.opts.Sources, pkg.ImportPath),
+			BranchRate: "0",
+			Complexity: "0",
+			Classes:    []coberturaClass{},
+		}
+
+		pkgLines, pkgCovered := 0, 0
+		for _, file := range pkg.Files {
+			hits := lineHits(file.Blocks)
+			lines, covered := countLines(hits)
+			pkgLines += lines
+			pkgCovered += covered
+
+			class := coberturaClass{
+				Name:       strings.TrimSuffix(file.FileName, ".go"),
+				Filename:   trimModulePath(cg.opts.Sources, file.FilePath),
+				LineRate:   formatRate(covered, lines),
+				BranchRate: "0",
+				Complexity: "0",
+				Methods:    []coberturaMethod{},
+				Lines:      coberturaLines(hits, 0, 0),
+			}
+
+			for _, fn := range functionsByFile[file.FilePath] {
+				methodLines := coberturaLines(hits, fn.StartLine, fn.EndLine)
+				methodCovered := 0
+				for _, line := range methodLines {
+					if line.Hits > 0 {
+						methodCovered++
+					}
+				}
+
+				class.Methods = append(class.Methods, coberturaMethod{
+					Name:       fn.FullName(),
+					Signature:  "",
+					LineRate:   formatRate(methodCovered, len(methodLines)),
+					BranchRate: "0",
+					Complexity: "0",
+					Lines:      methodLines,
+				})
+			}
+
+			xmlPkg.Classes = append(xmlPkg.Classes, class)
+		}
+
+		xmlPkg.LineRate = formatRate(pkgCovered, pkgLines)
+		doc.Packages = append(doc.Packages, xmlPkg)
+		totalLines += pkgLines
+		totalCovered += pkgCovered
+	}
+
+	doc.LinesValid = totalLines
+	doc.LinesCovered = totalCovered
+	doc.LineRate = formatRate(totalCovered, totalLines)
+
+	if _, err := io.WriteString(w, xml.Header+coberturaDoctype+"\n"); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("erro ao gerar XML Cobertura: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// lineHits expande os blocos em linhas, usando o maior contador entre os
+// blocos que tocam cada linha
+func lineHits(blocks []CoverageBlock) map[int]int {
+	hits := make(map[int]int)
+	for _, block := range blocks {
+		for line := block.StartLine; line <= block.EndLine; line++ {
+			if current, ok := hits[line]; !ok || block.Count > current {
+				hits[line] = block.Count
+			}
+		}
+	}
+	return hits
+}
+
+func countLines(hits map[int]int) (lines, covered int) {
+	for _, count := range hits {
+		lines++
+		if count > 0 {
+			covered++
+		}
+	}
+	return lines, covered
+}
+
+// coberturaLines retorna as linhas ordenadas, opcionalmente limitadas ao
+// intervalo [from, to] (0 para todas)
+func coberturaLines(hits map[int]int, from, to int) []coberturaLine {
+	lines := []coberturaLine{}
+	for number, count := range hits {
+		if to > 0 && (number < from || number > to) {
+			continue
+		}
+		lines = append(lines, coberturaLine{Number: number, Hits: count, Branch: "false"})
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].Number < lines[j].Number
+	})
+	return lines
+}
+
+// formatRate formata a razão covered/total entre 0 e 1
+func formatRate(covered, total int) string {
+	if total == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(float64(covered)/float64(total), 'f', 4, 64)
+}
@@ -0,0 +1,221 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dtdElement é a declaração de um elemento no DTD
+type dtdElement struct {
+	model    *regexp.Regexp // sequência de filhos, cada um seguido de ","
+	pcdata   bool
+	attrs    map[string]bool // atributo -> obrigatório
+	required []string
+}
+
+var (
+	dtdComment  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	dtdElemDecl = regexp.MustCompile(`<!ELEMENT\s+(\S+)\s+([^>]+)>`)
+	dtdAttrDecl = regexp.MustCompile(`<!ATTLIST\s+(\S+)\s+(\S+)\s+CDATA\s+(#REQUIRED|#IMPLIED|"[^"]*")\s*>`)
+	dtdName     = regexp.MustCompile(`[A-Za-z_][\w.-]*`)
+)
+
+// loadDTD interpreta o subconjunto de DTD usado pelo Cobertura: modelos de
+// conteúdo com sequências, ?, * e + e atributos CDATA
+func loadDTD(t *testing.T, path string) map[string]*dtdElement {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := dtdComment.ReplaceAllString(string(data), "")
+
+	elements := make(map[string]*dtdElement)
+	for _, m := range dtdElemDecl.FindAllStringSubmatch(text, -1) {
+		elem := &dtdElement{attrs: make(map[string]bool)}
+		model := strings.TrimSpace(m[2])
+
+		switch model {
+		case "EMPTY":
+			elem.model = regexp.MustCompile(`^$`)
+		case "(#PCDATA)":
+			elem.model = regexp.MustCompile(`^$`)
+			elem.pcdata = true
+		default:
+			// Sequências viram concatenação; cada nome casa com "nome,"
+			pattern := dtdName.ReplaceAllStringFunc(model, func(name string) string {
+				return "(?:" + regexp.QuoteMeta(name) + ";)"
+			})
+			pattern = strings.NewReplacer(",", "", " ", "", ";", ",").Replace(pattern)
+			elem.model = regexp.MustCompile("^" + pattern + "$")
+		}
+		elements[m[1]] = elem
+	}
+
+	for _, m := range dtdAttrDecl.FindAllStringSubmatch(text, -1) {
+		elem, ok := elements[m[1]]
+		if !ok {
+			t.Fatalf("ATTLIST para elemento não declarado: %s", m[1])
+		}
+		elem.attrs[m[2]] = m[3] == "#REQUIRED"
+	}
+	return elements
+}
+
+// validateXML valida o documento contra as declarações do DTD, exigindo
+// <coverage> como elemento raiz
+func validateXML(elements map[string]*dtdElement, data []byte) error {
+	type frame struct {
+		name     string
+		children strings.Builder
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*frame
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch tok := token.(type) {
+		case xml.StartElement:
+			name := tok.Name.Local
+			elem, ok := elements[name]
+			if !ok {
+				return fmt.Errorf("elemento não declarado: <%s>", name)
+			}
+
+			if len(stack) == 0 && name != "coverage" {
+				return fmt.Errorf("elemento raiz inesperado: <%s>", name)
+			}
+
+			seen := make(map[string]bool)
+			for _, attr := range tok.Attr {
+				if _, ok := elem.attrs[attr.Name.Local]; !ok {
+					return fmt.Errorf("<%s>: atributo não declarado %q", name, attr.Name.Local)
+				}
+				seen[attr.Name.Local] = true
+			}
+			for attr, required := range elem.attrs {
+				if required && !seen[attr] {
+					return fmt.Errorf("<%s>: atributo obrigatório ausente %q", name, attr)
+				}
+			}
+
+			if len(stack) > 0 {
+				stack[len(stack)-1].children.WriteString(name + ",")
+			}
+			stack = append(stack, &frame{name: name})
+
+		case xml.EndElement:
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !elements[top.name].model.MatchString(top.children.String()) {
+				return fmt.Errorf("<%s>: filhos inválidos %q", top.name, top.children.String())
+			}
+
+		case xml.CharData:
+			if len(stack) > 0 && strings.TrimSpace(string(tok)) != "" && !elements[stack[len(stack)-1].name].pcdata {
+				return fmt.Errorf("<%s>: texto não permitido", stack[len(stack)-1].name)
+			}
+		}
+	}
+	return nil
+}
+
+func TestCoberturaValidatorRejectsInvalid(t *testing.T) {
+	elements := loadDTD(t, "testdata/coverage-04.dtd")
+
+	invalid := []string{
+		`<coverage/>`,
+		`<packages></packages>`,
+		`<coverage line-rate="1" branch-rate="0" lines-covered="0" lines-valid="0" branches-covered="0" branches-valid="0" complexity="0" version="x" timestamp="0"><packages/><sources/></coverage>`,
+		`<coverage line-rate="1" branch-rate="0" lines-covered="0" lines-valid="0" branches-covered="0" branches-valid="0" complexity="0" version="x" timestamp="0" extra="1"><packages/></coverage>`,
+	}
+	for _, doc := range invalid {
+		if err := validateXML(elements, []byte(doc)); err == nil {
+			t.Errorf("documento deveria ser inválido: %s", doc)
+		}
+	}
+}
+
+func TestCoberturaGenerator(t *testing.T) {
+	root := writeTestModule(t)
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cov := mustParse(t, `mode: count
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 5
+example.com/demo/pkg/calc/sum.go:4.11,6.3 1 0
+example.com/demo/pkg/calc/sum.go:7.2,7.14 1 5
+example.com/demo/pkg/other/x.go:1.1,1.10 1 0
+`)
+	functions, err := AnalyzeFunctions(cov, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generator := NewCoberturaGenerator(cov, CoberturaOptions{
+		Sources:   resolver,
+		Functions: functions,
+		Timestamp: time.UnixMilli(1700000000000),
+	})
+
+	var buf bytes.Buffer
+	if err := generator.Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar XML: %v", err)
+	}
+
+	if err := validateXML(loadDTD(t, "testdata/coverage-04.dtd"), buf.Bytes()); err != nil {
+		t.Fatalf("XML inválido segundo o DTD: %v\n%s", err, buf.String())
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">`,
+		`line-rate="0.5000" branch-rate="0" lines-covered="3" lines-valid="6"`,
+		`timestamp="1700000000000"`,
+		`<source>` + root + `</source>`,
+		`<package name="pkg/calc" line-rate="0.6000"`,
+		`<class name="sum" filename="pkg/calc/sum.go" line-rate="0.6000"`,
+		`<method name="Sum" signature="" line-rate="0.6000"`,
+		`<line number="4" hits="5" branch="false"></line>`,
+		`<line number="5" hits="0" branch="false"></line>`,
+		`<package name="pkg/other" line-rate="0.0000"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("XML não contém %q", want)
+		}
+	}
+}
+
+func TestCoberturaGeneratorWithoutSources(t *testing.T) {
+	cov := mustParse(t, "mode: set\npkg/a.go:1.1,2.2 1 1\n")
+
+	var buf bytes.Buffer
+	if err := NewCoberturaGenerator(cov, CoberturaOptions{}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validateXML(loadDTD(t, "testdata/coverage-04.dtd"), buf.Bytes()); err != nil {
+		t.Fatalf("XML inválido segundo o DTD: %v", err)
+	}
+	if strings.Contains(buf.String(), "<sources>") || !strings.Contains(buf.String(), `filename="pkg/a.go"`) {
+		t.Errorf("XML inesperado:\n%s", buf.String())
+	}
+}
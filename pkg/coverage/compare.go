@@ -0,0 +1,191 @@
+package coverage
+
+import (
+	"sort"
+)
+
+// Situação de um arquivo ou pacote na comparação entre dois perfis
+const (
+	DeltaAdded     = "added"     // existe apenas no perfil head
+	DeltaRemoved   = "removed"   // existe apenas no perfil base
+	DeltaChanged   = "changed"   // cobertura ou statements mudaram
+	DeltaUnchanged = "unchanged" // mesma cobertura e statements
+)
+
+// CoverageDelta compara a cobertura de um arquivo ou pacote nos dois perfis.
+// Delta é a diferença em pontos percentuais (head - base) e vale zero para
+// alvos adicionados ou removidos.
+type CoverageDelta struct {
+	Path        string
+	Status      string
+	BaseTotal   int
+	BaseCovered int
+	HeadTotal   int
+	HeadCovered int
+	Base        float64
+	Head        float64
+	Delta       float64
+}
+
+// UncoveredBlock é um bloco sem cobertura no perfil head que não estava
+// descoberto na base: ou é código novo ou deixou de ser executado
+type UncoveredBlock struct {
+	FilePath string
+	Block    CoverageBlock
+	// WasCovered indica que o bloco existia e estava coberto na base
+	WasCovered bool
+}
+
+// CoverageDiff é o resultado da comparação entre um perfil base e um head
+type CoverageDiff struct {
+	BaseTotal  float64
+	HeadTotal  float64
+	TotalDelta float64 // pontos percentuais
+
+	Files    []CoverageDelta // todos os arquivos, ordenados por caminho
+	Packages []CoverageDelta // todos os pacotes, ordenados por caminho
+
+	AddedFiles     []string
+	RemovedFiles   []string
+	NewlyUncovered []UncoveredBlock // ordenados por arquivo e posição
+}
+
+// CompareCoverage compara dois perfis de cobertura
+func CompareCoverage(base, head *ProjectCoverage) *CoverageDiff {
+	diff := &CoverageDiff{
+		BaseTotal: base.GetTotalCoverage(),
+		HeadTotal: head.GetTotalCoverage(),
+	}
+	diff.TotalDelta = diff.HeadTotal - diff.BaseTotal
+
+	baseFiles := make(map[string]stmtCounts)
+	for path, file := range base.Files {
+		baseFiles[path] = stmtCounts{file.TotalStmt, file.CoveredStmt}
+	}
+	headFiles := make(map[string]stmtCounts)
+	for path, file := range head.Files {
+		headFiles[path] = stmtCounts{file.TotalStmt, file.CoveredStmt}
+	}
+	diff.Files = compareCounts(baseFiles, headFiles)
+
+	basePkgs := make(map[string]stmtCounts)
+	for _, pkg := range base.GetPackages() {
+		basePkgs[pkg.ImportPath] = stmtCounts{pkg.TotalStmt, pkg.CoveredStmt}
+	}
+	headPkgs := make(map[string]stmtCounts)
+	for _, pkg := range head.GetPackages() {
+		headPkgs[pkg.ImportPath] = stmtCounts{pkg.TotalStmt, pkg.CoveredStmt}
+	}
+	diff.Packages = compareCounts(basePkgs, headPkgs)
+
+	for _, delta := range diff.Files {
+		switch delta.Status {
+		case DeltaAdded:
+			diff.AddedFiles = append(diff.AddedFiles, delta.Path)
+		case DeltaRemoved:
+			diff.RemovedFiles = append(diff.RemovedFiles, delta.Path)
+		}
+	}
+
+	diff.NewlyUncovered = newlyUncovered(base, head)
+	return diff
+}
+
+// FileDelta retorna a comparação de um arquivo, se existir em algum perfil
+func (cd *CoverageDiff) FileDelta(filePath string) (CoverageDelta, bool) {
+	i := sort.Search(len(cd.Files), func(i int) bool {
+		return cd.Files[i].Path >= filePath
+	})
+	if i < len(cd.Files) && cd.Files[i].Path == filePath {
+		return cd.Files[i], true
+	}
+	return CoverageDelta{}, false
+}
+
+type stmtCounts struct {
+	total, covered int
+}
+
+func (sc stmtCounts) percent() float64 {
+	if sc.total == 0 {
+		return 0
+	}
+	return float64(sc.covered) / float64(sc.total) * 100
+}
+
+func compareCounts(base, head map[string]stmtCounts) []CoverageDelta {
+	paths := make(map[string]bool)
+	for path := range base {
+		paths[path] = true
+	}
+	for path := range head {
+		paths[path] = true
+	}
+
+	deltas := make([]CoverageDelta, 0, len(paths))
+	for path := range paths {
+		b, inBase := base[path]
+		h, inHead := head[path]
+
+		delta := CoverageDelta{
+			Path:        path,
+			BaseTotal:   b.total,
+			BaseCovered: b.covered,
+			HeadTotal:   h.total,
+			HeadCovered: h.covered,
+			Base:        b.percent(),
+			Head:        h.percent(),
+		}
+
+		switch {
+		case !inBase:
+			delta.Status = DeltaAdded
+		case !inHead:
+			delta.Status = DeltaRemoved
+		case b == h:
+			delta.Status = DeltaUnchanged
+		default:
+			delta.Status = DeltaChanged
+			delta.Delta = delta.Head - delta.Base
+		}
+
+		deltas = append(deltas, delta)
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].Path < deltas[j].Path
+	})
+	return deltas
+}
+
+func newlyUncovered(base, head *ProjectCoverage) []UncoveredBlock {
+	var blocks []UncoveredBlock
+
+	for _, file := range head.GetSortedFiles() {
+		baseCounts := make(map[blockKey]int)
+		if baseFile, ok := base.Files[file.FilePath]; ok {
+			for _, block := range baseFile.Blocks {
+				baseCounts[keyOf(block)] += block.Count
+			}
+		}
+
+		for _, block := range file.Blocks {
+			if block.Count > 0 || block.NumStmt == 0 {
+				continue
+			}
+
+			baseCount, existed := baseCounts[keyOf(block)]
+			if existed && baseCount == 0 {
+				continue
+			}
+
+			blocks = append(blocks, UncoveredBlock{
+				FilePath:   file.FilePath,
+				Block:      block,
+				WasCovered: existed,
+			})
+		}
+	}
+
+	return blocks
+}
@@ -0,0 +1,214 @@
+package coverage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxUncoveredBlocksMarkdown limita a lista de blocos novos sem cobertura
+// no resumo Markdown
+const maxUncoveredBlocksMarkdown = 50
+
+// CompareMarkdownOptions configura o resumo Markdown de uma comparação
+type CompareMarkdownOptions struct {
+	// MaxLength limita o tamanho da saída em caracteres (padrão
+	// GitHubCommentLimit). Linhas das tabelas e blocos que não cabem são
+	// omitidos com um aviso.
+	MaxLength int
+	// Language é o idioma dos textos e da formatação de números (padrão
+	// DefaultLanguage)
+	Language Language
+}
+
+// WriteMarkdown escreve um resumo da comparação em Markdown, pronto para ser
+// publicado como comentário de pull request. Apenas pacotes e arquivos com
+// alteração são listados.
+func (cd *CoverageDiff) WriteMarkdown(w io.Writer) error {
+	return cd.WriteMarkdownWithOptions(w, CompareMarkdownOptions{})
+}
+
+// WriteLocalizedMarkdown escreve o resumo de WriteMarkdown no idioma
+// informado
+func (cd *CoverageDiff) WriteLocalizedMarkdown(w io.Writer, lang Language) error {
+	return cd.WriteMarkdownWithOptions(w, CompareMarkdownOptions{Language: lang})
+}
+
+// WriteMarkdownWithOptions escreve o resumo de WriteMarkdown com as opções
+// informadas
+func (cd *CoverageDiff) WriteMarkdownWithOptions(w io.Writer, opts CompareMarkdownOptions) error {
+	lang := opts.Language
+	if err := lang.Validate(); err != nil {
+		return err
+	}
+	maxLength := opts.MaxLength
+	if maxLength <= 0 {
+		maxLength = GitHubCommentLimit
+	}
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "## 📊 %s (%s)\n\n", lang.tr("Cobertura: %s", lang.percent(cd.HeadTotal)), formatDeltaText(cd.TotalDelta, lang))
+	sb.WriteString("| | Base | Head | Δ |\n")
+	sb.WriteString("|---|---:|---:|---:|\n")
+	fmt.Fprintf(&sb, "| **Total** | %s | %s | %s |\n", lang.percent(cd.BaseTotal), lang.percent(cd.HeadTotal), formatDelta(cd.TotalDelta, lang))
+
+	sections := []markdownSection{
+		deltaSection(lang, lang.text("Pacotes"), lang.text("Pacote"), cd.Packages),
+		deltaSection(lang, lang.text("Arquivos"), lang.text("Arquivo"), cd.Files),
+		cd.uncoveredSection(lang),
+	}
+	if err := writeMarkdownSections(&sb, sections, maxLength, lang, func(count int) string {
+		return omittedItemsNote(lang, count)
+	}); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// markdownSection é uma tabela ou lista de um resumo Markdown; o
+// cabeçalho só é escrito junto com a primeira linha
+type markdownSection struct {
+	header string
+	rows   []markdownRow
+}
+
+// text retorna a linha i, precedida do cabeçalho na primeira
+func (ms markdownSection) text(i int) string {
+	if i == 0 {
+		return ms.header + ms.rows[0].text
+	}
+	return ms.rows[i].text
+}
+
+// writeMarkdownSections acrescenta as seções a sb sem que o resumo passe de
+// maxLength caracteres. Se nem tudo cabe, cada linha só é escrita se sobrar
+// espaço para o aviso note com os itens seguintes, então o aviso sempre
+// cabe; se nem o aviso com todos os itens cabe, retorna erro.
+func writeMarkdownSections(sb *strings.Builder, sections []markdownSection, maxLength int, lang Language, note func(count int) string) error {
+	// rest é o tamanho de todas as linhas, com os cabeçalhos
+	remaining, rest := 0, 0
+	for _, section := range sections {
+		for i, row := range section.rows {
+			remaining += row.items
+			rest += utf8.RuneCountInString(section.text(i))
+		}
+	}
+
+	length := utf8.RuneCountInString(sb.String())
+	truncate := length+rest > maxLength
+	if truncate && length+utf8.RuneCountInString(note(remaining)) > maxLength {
+		return errors.New(lang.tr("resumo Markdown excede o limite de %s caracteres", lang.number(maxLength)))
+	}
+
+	for _, section := range sections {
+		for i, row := range section.rows {
+			text := section.text(i)
+			textLength := utf8.RuneCountInString(text)
+			if truncate && length+textLength+utf8.RuneCountInString(note(remaining-row.items)) > maxLength {
+				sb.WriteString(note(remaining))
+				return nil
+			}
+			sb.WriteString(text)
+			length += textLength
+			remaining -= row.items
+		}
+	}
+	return nil
+}
+
+// markdownRow é uma linha de uma seção e a quantidade de itens (pacotes,
+// arquivos ou blocos) que ela representa
+type markdownRow struct {
+	text  string
+	items int
+}
+
+// omittedItemsNote retorna o aviso de itens omitidos pelo limite de tamanho
+func omittedItemsNote(lang Language, count int) string {
+	if count <= 0 {
+		return ""
+	}
+	return "\n> " + lang.plural(count, "⚠️ %d item omitido para respeitar o limite de tamanho do comentário.", "⚠️ %d itens omitidos para respeitar o limite de tamanho do comentário.") + "\n"
+}
+
+func (cd *CoverageDiff) uncoveredSection(lang Language) markdownSection {
+	var section markdownSection
+	if len(cd.NewlyUncovered) == 0 {
+		return section
+	}
+
+	section.header = fmt.Sprintf("\n### %s\n\n", lang.tr("Blocos novos sem cobertura (%d)", len(cd.NewlyUncovered)))
+	for i, ub := range cd.NewlyUncovered {
+		if i == maxUncoveredBlocksMarkdown {
+			rest := len(cd.NewlyUncovered) - i
+			section.rows = append(section.rows, markdownRow{fmt.Sprintf("- %s\n", lang.plural(rest, "… e mais %d bloco", "… e mais %d blocos")), rest})
+			break
+		}
+
+		note := lang.text("código novo")
+		if ub.WasCovered {
+			note = lang.text("**deixou de ser coberto**")
+		}
+		b := ub.Block
+		section.rows = append(section.rows, markdownRow{fmt.Sprintf("- `%s:%d.%d,%d.%d` (%s) — %s\n",
+			escapeMarkdownCell(ub.FilePath), b.StartLine, b.StartCol, b.EndLine, b.EndCol, lang.tr("%d statements", b.NumStmt), note), 1})
+	}
+	return section
+}
+
+func deltaSection(lang Language, title, column string, deltas []CoverageDelta) markdownSection {
+	var section markdownSection
+	for _, delta := range deltas {
+		if delta.Status == DeltaUnchanged {
+			continue
+		}
+
+		base := lang.percent(delta.Base)
+		head := lang.percent(delta.Head)
+		change := formatDelta(delta.Delta, lang)
+
+		switch delta.Status {
+		case DeltaAdded:
+			base, change = "—", "🆕"
+		case DeltaRemoved:
+			head, change = "—", "🗑️"
+		}
+
+		section.rows = append(section.rows, markdownRow{fmt.Sprintf("| `%s` | %s | %s | %s |\n", escapeMarkdownCell(delta.Path), base, head, change), 1})
+	}
+
+	section.header = fmt.Sprintf("\n### %s\n\n| %s | Base | Head | Δ |\n|---|---:|---:|---:|\n", title, column)
+	return section
+}
+
+// formatDelta formata uma variação em pontos percentuais com sinal
+func formatDelta(delta float64, lang Language) string {
+	switch {
+	case delta >= 0.05:
+		return "🔼 " + lang.signed(delta)
+	case delta <= -0.05:
+		return "🔽 " + lang.decimal(delta, 1)
+	default:
+		return "➖ " + lang.decimal(0, 1)
+	}
+}
+
+func formatDeltaText(delta float64, lang Language) string {
+	if delta >= 0.05 || delta <= -0.05 {
+		return lang.tr("%s pp", lang.signed(delta))
+	}
+	return lang.text("sem alteração")
+}
+
+// escapeMarkdownCell evita que caminhos quebrem as tabelas e os trechos de
+// código do Markdown
+func escapeMarkdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	text = strings.ReplaceAll(text, "`", "'")
+	return strings.ReplaceAll(text, "\n", " ")
+}
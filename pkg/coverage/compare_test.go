@@ -0,0 +1,197 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+const compareBase = `mode: set
+example.com/app/a/keep.go:1.1,2.2 4 1
+example.com/app/a/keep.go:3.1,4.2 4 0
+example.com/app/a/same.go:1.1,2.2 2 1
+example.com/app/b/gone.go:1.1,2.2 3 1
+`
+
+const compareHead = `mode: set
+example.com/app/a/keep.go:1.1,2.2 4 0
+example.com/app/a/keep.go:3.1,4.2 4 0
+example.com/app/a/same.go:1.1,2.2 2 1
+example.com/app/c/new.go:1.1,2.2 5 1
+example.com/app/c/new.go:3.1,4.2 1 0
+`
+
+func TestCompareCoverage(t *testing.T) {
+	base := mustParse(t, compareBase)
+	head := mustParse(t, compareHead)
+
+	diff := CompareCoverage(base, head)
+
+	// base: 9/13, head: 7/16
+	if d := diff.TotalDelta - (7.0/16*100 - 9.0/13*100); d > 1e-9 || d < -1e-9 {
+		t.Errorf("delta total = %.4f", diff.TotalDelta)
+	}
+
+	wantFiles := map[string]string{
+		"example.com/app/a/keep.go": DeltaChanged,
+		"example.com/app/a/same.go": DeltaUnchanged,
+		"example.com/app/b/gone.go": DeltaRemoved,
+		"example.com/app/c/new.go":  DeltaAdded,
+	}
+	if len(diff.Files) != len(wantFiles) {
+		t.Fatalf("esperava %d arquivos, obteve %d", len(wantFiles), len(diff.Files))
+	}
+	for _, delta := range diff.Files {
+		if delta.Status != wantFiles[delta.Path] {
+			t.Errorf("%s: status = %s, esperado %s", delta.Path, delta.Status, wantFiles[delta.Path])
+		}
+	}
+
+	keep, ok := diff.FileDelta("example.com/app/a/keep.go")
+	if !ok || keep.Delta != -50 {
+		t.Errorf("delta de keep.go = %+v", keep)
+	}
+
+	if len(diff.Packages) != 3 {
+		t.Fatalf("esperava 3 pacotes, obteve %d", len(diff.Packages))
+	}
+	if diff.Packages[0].Path != "example.com/app/a" || diff.Packages[0].Status != DeltaChanged {
+		t.Errorf("pacote a = %+v", diff.Packages[0])
+	}
+
+	if len(diff.AddedFiles) != 1 || diff.AddedFiles[0] != "example.com/app/c/new.go" {
+		t.Errorf("arquivos adicionados = %v", diff.AddedFiles)
+	}
+	if len(diff.RemovedFiles) != 1 || diff.RemovedFiles[0] != "example.com/app/b/gone.go" {
+		t.Errorf("arquivos removidos = %v", diff.RemovedFiles)
+	}
+
+	want := []UncoveredBlock{
+		{FilePath: "example.com/app/a/keep.go", Block: CoverageBlock{1, 1, 2, 2, 4, 0}, WasCovered: true},
+		{FilePath: "example.com/app/c/new.go", Block: CoverageBlock{3, 1, 4, 2, 1, 0}},
+	}
+	if len(diff.NewlyUncovered) != len(want) {
+		t.Fatalf("blocos novos sem cobertura = %v", diff.NewlyUncovered)
+	}
+	for i := range want {
+		if diff.NewlyUncovered[i] != want[i] {
+			t.Errorf("bloco %d = %+v, esperado %+v", i, diff.NewlyUncovered[i], want[i])
+		}
+	}
+}
+
+func TestCoverageDiffWriteMarkdown(t *testing.T) {
+	diff := CompareCoverage(mustParse(t, compareBase), mustParse(t, compareHead))
+
+	var buf bytes.Buffer
+	if err := diff.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("erro ao gerar Markdown: %v", err)
+	}
+
+	md := buf.String()
+	for _, want := range []string{
+		"## 📊 Cobertura: 43,8% (-25,5 pp)",
+		"| **Total** | 69,2% | 43,8% | 🔽 -25,5 |",
+		"| `example.com/app/a/keep.go` | 50,0% | 0,0% | 🔽 -50,0 |",
+		"| `example.com/app/c/new.go` | — | 83,3% | 🆕 |",
+		"| `example.com/app/b/gone.go` | 100,0% | — | 🗑️ |",
+		"`example.com/app/a/keep.go:1.1,2.2` (4 statements) — **deixou de ser coberto**",
+		"`example.com/app/c/new.go:3.1,4.2` (1 statements) — código novo",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown não contém %q:\n%s", want, md)
+		}
+	}
+
+	if strings.Contains(md, "same.go") {
+		t.Error("arquivos sem alteração não deveriam ser listados")
+	}
+}
+
+func TestCoverageDiffMarkdownEscaping(t *testing.T) {
+	base := mustParse(t, "mode: set\nexample.com/app/a.go:1.1,2.2 1 1\n")
+	head := mustParse(t, "mode: set\nexample.com/app/a.go:1.1,2.2 1 1\nexample.com/app/x`y|z.go:1.1,2.2 1 0\n")
+
+	var buf bytes.Buffer
+	if err := CompareCoverage(base, head).WriteMarkdown(&buf); err != nil {
+		t.Fatalf("erro ao gerar Markdown: %v", err)
+	}
+
+	md := buf.String()
+	if want := "- `example.com/app/x'y\\|z.go:1.1,2.2` (1 statements) — código novo"; !strings.Contains(md, want) {
+		t.Errorf("Markdown não contém %q:\n%s", want, md)
+	}
+	if strings.Contains(md, "x`y") {
+		t.Errorf("caminho com crase não escapado:\n%s", md)
+	}
+}
+
+func TestCoverageDiffMarkdownMaxLength(t *testing.T) {
+	var base, head strings.Builder
+	base.WriteString("mode: set\n")
+	head.WriteString("mode: set\n")
+	for i := range 3000 {
+		fmt.Fprintf(&base, "example.com/app/pkg%d/file%d.go:1.1,2.2 2 1\n", i%40, i)
+		fmt.Fprintf(&head, "example.com/app/pkg%d/file%d.go:1.1,2.2 2 0\n", i%40, i)
+	}
+	diff := CompareCoverage(mustParse(t, base.String()), mustParse(t, head.String()))
+
+	var buf bytes.Buffer
+	if err := diff.WriteMarkdown(&buf); err != nil {
+		t.Fatalf("erro ao gerar Markdown: %v", err)
+	}
+	md := buf.String()
+	if n := utf8.RuneCountInString(md); n > GitHubCommentLimit {
+		t.Errorf("Markdown com %d caracteres, limite %d", n, GitHubCommentLimit)
+	}
+	if !strings.Contains(md, "### Pacotes") || !strings.Contains(md, "itens omitidos") {
+		t.Errorf("esperados pacotes e o aviso de itens omitidos:\n%s", md[len(md)-300:])
+	}
+
+	// Todos os limites entre o cabeçalho e a saída completa são respeitados
+	small := CompareCoverage(mustParse(t, compareBase), mustParse(t, compareHead))
+	var complete bytes.Buffer
+	small.WriteMarkdown(&complete)
+	fullLength := utf8.RuneCountInString(complete.String())
+	for limit := 100; limit <= fullLength+1; limit++ {
+		var out bytes.Buffer
+		if err := small.WriteMarkdownWithOptions(&out, CompareMarkdownOptions{MaxLength: limit}); err != nil {
+			continue
+		}
+		if n := utf8.RuneCountInString(out.String()); n > limit {
+			t.Fatalf("limite %d: Markdown com %d caracteres", limit, n)
+		}
+		if limit >= fullLength && out.String() != complete.String() {
+			t.Fatalf("limite %d: saída completa truncada", limit)
+		}
+	}
+
+	err := small.WriteMarkdownWithOptions(&buf, CompareMarkdownOptions{MaxLength: 10, Language: LanguageEnglish})
+	if err == nil || err.Error() != "Markdown summary exceeds the limit of 10 characters" {
+		t.Errorf("erro = %v, esperado o erro de limite em inglês", err)
+	}
+}
+
+func TestHTMLGenerationWithDiff(t *testing.T) {
+	head := mustParse(t, compareHead)
+	diff := CompareCoverage(mustParse(t, compareBase), head)
+
+	var buf bytes.Buffer
+	if err := NewHTMLGeneratorWithOptions(head, HTMLOptions{Diff: diff}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar HTML: %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{
+		"Variação vs. Base",
+		`<span class="file-delta delta-down">-50,0</span>`,
+		`<span class="file-delta delta-none">novo</span>`,
+		`<span class="file-delta delta-none">&#43;0,0</span>`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML não contém %q", want)
+		}
+	}
+}
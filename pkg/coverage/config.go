@@ -0,0 +1,223 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ConfigFormat é o formato de um arquivo de configuração
+type ConfigFormat string
+
+// Formatos aceitos por ParseConfig
+const (
+	ConfigYAML ConfigFormat = "yaml"
+	ConfigJSON ConfigFormat = "json"
+)
+
+// DefaultConfigFiles são os nomes procurados quando nenhum arquivo de
+// configuração é informado, em ordem de preferência
+var DefaultConfigFiles = []string{".coverage-report.yml", ".coverage-report.yaml", ".coverage-report.json"}
+
+// Config é a configuração de geração do relatório, normalmente lida de
+// .coverage-report.yml. Os campos de entrada e saída são caminhos; os demais
+// alimentam diretamente as opções dos geradores.
+//
+// Exemplo:
+//
+//	title: Cobertura do app
+//	language: en
+//	source: .
+//	inputs: [coverage.out]
+//	validation: strict
+//	filters:
+//	  exclude: ["*.pb.go", "**/vendor/**"]
+//	thresholds:
+//	  total: 80
+//	  packages:
+//	    - pattern: example.com/app/internal/legacy
+//	      min: 30
+//	outputs:
+//	  html: coverage-report.html
+//	  badge: coverage.svg
+//	badge:
+//	  label: cobertura
+//	buckets:
+//	  - name: excellent
+//	    label: Excelente
+//	    min: 90
+//	    color: "#2ea44f"
+//	  - name: good
+//	    label: Bom
+//	    min: 75
+//	    color: "#0366d6"
+//	  - name: poor
+//	    label: Fraco
+//	    min: 0
+//	    color: "#e05d44"
+type Config struct {
+	Title      string        `json:"title,omitempty"`      // título do relatório HTML
+	Language   Language      `json:"language,omitempty"`   // idioma dos relatórios (pt-BR, en ou es)
+	Templates  string        `json:"templates,omitempty"`  // diretório com templates do HTML (ver HTMLOptions.Templates)
+	Source     string        `json:"source,omitempty"`     // raiz do módulo com o código-fonte
+	Inputs     []string      `json:"inputs,omitempty"`     // perfis de cobertura
+	CoverDirs  []string      `json:"coverDirs,omitempty"`  // diretórios GOCOVERDIR
+	Base       string        `json:"base,omitempty"`       // perfil base para comparação
+	Validation string        `json:"validation,omitempty"` // "", "strict" ou "lenient"
+	Filters    FilterOptions `json:"filters"`
+
+	Thresholds ThresholdRules `json:"thresholds"`
+	PatchMin   float64        `json:"patchMin,omitempty"` // cobertura mínima das linhas alteradas
+
+	Outputs OutputConfig    `json:"outputs"`
+	Badge   BadgeOptions    `json:"badge"`
+	Buckets CoverageBuckets `json:"buckets,omitempty"` // faixas de cobertura de todas as saídas
+}
+
+// OutputConfig são os arquivos gerados; campos vazios desativam a saída
+type OutputConfig struct {
+	HTML         string `json:"html,omitempty"`
+	Site         string `json:"site,omitempty"` // diretório do site estático
+	JSON         string `json:"json,omitempty"`
+	Markdown     string `json:"markdown,omitempty"`
+	DiffMarkdown string `json:"diffMarkdown,omitempty"` // resumo da comparação com Base
+	Cobertura    string `json:"cobertura,omitempty"`
+	LCOV         string `json:"lcov,omitempty"`
+	Badge        string `json:"badge,omitempty"`
+}
+
+// LoadConfig lê um arquivo de configuração YAML (.yml, .yaml) ou JSON
+// (.json), detectando o formato pela extensão
+func LoadConfig(path string) (*Config, error) {
+	var format ConfigFormat
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		format = ConfigYAML
+	case ".json":
+		format = ConfigJSON
+	default:
+		return nil, fmt.Errorf("formato do arquivo de configuração %s não suportado (use .yml, .yaml ou .json)", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir configuração: %w", err)
+	}
+	defer file.Close()
+
+	config, err := ParseConfig(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig lê e valida uma configuração. Chaves desconhecidas são
+// rejeitadas. O YAML aceito é o subconjunto usado em arquivos de
+// configuração: mapas e listas em bloco, listas em linha e escalares.
+func ParseConfig(reader io.Reader, format ConfigFormat) (*Config, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler configuração: %w", err)
+	}
+
+	switch format {
+	case ConfigJSON:
+	case ConfigYAML:
+		// O YAML é convertido para JSON para reaproveitar a validação de
+		// chaves e tipos do encoding/json
+		value, err := parseYAML(data)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler configuração: %w", err)
+		}
+		if data, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("erro ao ler configuração: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("formato de configuração desconhecido: %q", format)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	config := &Config{}
+	if err := decoder.Decode(config); err != nil {
+		return nil, fmt.Errorf("erro ao ler configuração: %w", err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Validate verifica os valores que os geradores rejeitariam
+func (c *Config) Validate() error {
+	switch c.Validation {
+	case "", "strict", "lenient":
+	default:
+		return fmt.Errorf("validation deve ser strict ou lenient, obtido %q", c.Validation)
+	}
+	if _, err := ParseLanguage(string(c.Language)); err != nil {
+		return fmt.Errorf("language: %w", err)
+	}
+	if c.PatchMin < 0 || c.PatchMin > 100 {
+		return fmt.Errorf("patchMin deve estar entre 0 e 100, obtido %.1f", c.PatchMin)
+	}
+	if c.Badge.Precision < 0 || c.Badge.Precision > maxBadgePrecision {
+		return fmt.Errorf("badge.precision deve estar entre 0 e %d", maxBadgePrecision)
+	}
+	if c.Outputs.DiffMarkdown != "" && c.Base == "" {
+		return fmt.Errorf("outputs.diffMarkdown requer base")
+	}
+	if _, err := newPathFilter(c.Filters); err != nil {
+		return err
+	}
+	if err := c.Buckets.Validate(); err != nil {
+		return fmt.Errorf("buckets: %w", err)
+	}
+	return c.Thresholds.Validate()
+}
+
+// ParseOptions retorna as opções de parse correspondentes a Validation
+func (c *Config) ParseOptions() ParseOptions {
+	opts := ParseOptions{Language: c.language()}
+	switch c.Validation {
+	case "strict":
+		opts.Validation = ValidateStrict
+	case "lenient":
+		opts.Validation = ValidateLenient
+	}
+	return opts
+}
+
+// HTMLOptions retorna as opções do HTMLGenerator e do SiteGenerator
+// definidas na configuração. Com Source, o código-fonte é lido por um
+// SourceResolver nesse diretório.
+func (c *Config) HTMLOptions() (HTMLOptions, error) {
+	opts := HTMLOptions{Title: c.Title, Buckets: c.Buckets, Language: c.language()}
+	if c.Templates != "" {
+		opts.Templates = os.DirFS(c.Templates)
+	}
+	if c.Source != "" {
+		sources, err := NewSourceResolver(c.Source)
+		if err != nil {
+			return HTMLOptions{}, fmt.Errorf("source: %w", err)
+		}
+		opts.Sources = sources
+	}
+	return opts, nil
+}
+
+// language retorna o idioma normalizado (ex.: "en-US" vira en); códigos
+// inválidos são rejeitados por Validate
+func (c *Config) language() Language {
+	lang, err := ParseLanguage(string(c.Language))
+	if err != nil {
+		return DefaultLanguage
+	}
+	return lang
+}
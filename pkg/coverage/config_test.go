@@ -0,0 +1,257 @@
+package coverage
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleConfigYAML = `# Configuração do relatório
+title: Cobertura do app
+language: en-US
+templates: tema
+source: .
+inputs: [coverage.out, integration.out]
+coverDirs:
+  - covdata
+validation: strict
+filters:
+  exclude: ["*.pb.go", "**/vendor/**"]
+  excludeRegexp:
+    - /mocks?/
+  keepGenerated: false
+thresholds:
+  total: 80
+  packages:
+    - pattern: example.com/app/internal/legacy
+      min: 30
+patchMin: 90
+outputs:
+  html: coverage-report.html
+  badge: coverage.svg
+badge:
+  label: cobertura
+  precision: 1
+buckets:
+  - name: top
+    label: Ótimo
+    min: 90
+    color: "#2ea44f"
+  - name: rest
+    min: 0
+    color: "#e05d44"
+    emoji: 🔴
+`
+
+func sampleConfig() *Config {
+	return &Config{
+		Title:      "Cobertura do app",
+		Language:   "en-US",
+		Templates:  "tema",
+		Source:     ".",
+		Inputs:     []string{"coverage.out", "integration.out"},
+		CoverDirs:  []string{"covdata"},
+		Validation: "strict",
+		Filters: FilterOptions{
+			Exclude:       []string{"*.pb.go", "**/vendor/**"},
+			ExcludeRegexp: []string{"/mocks?/"},
+		},
+		Thresholds: ThresholdRules{
+			Total:    80,
+			Packages: []ThresholdRule{{Pattern: "example.com/app/internal/legacy", Min: 30}},
+		},
+		PatchMin: 90,
+		Outputs:  OutputConfig{HTML: "coverage-report.html", Badge: "coverage.svg"},
+		Badge:    BadgeOptions{Label: "cobertura", Precision: 1},
+		Buckets: CoverageBuckets{
+			{Name: "top", Label: "Ótimo", Min: 90, Color: "#2ea44f"},
+			{Name: "rest", Color: "#e05d44", Emoji: "🔴"},
+		},
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	jsonConfig := `{
+  "title": "Cobertura do app",
+  "language": "en-US",
+  "templates": "tema",
+  "source": ".",
+  "inputs": ["coverage.out", "integration.out"],
+  "coverDirs": ["covdata"],
+  "validation": "strict",
+  "filters": {"exclude": ["*.pb.go", "**/vendor/**"], "excludeRegexp": ["/mocks?/"]},
+  "thresholds": {"total": 80, "packages": [{"pattern": "example.com/app/internal/legacy", "min": 30}]},
+  "patchMin": 90,
+  "outputs": {"html": "coverage-report.html", "badge": "coverage.svg"},
+  "badge": {"label": "cobertura", "precision": 1},
+  "buckets": [{"name": "top", "label": "Ótimo", "min": 90, "color": "#2ea44f"}, {"name": "rest", "min": 0, "color": "#e05d44", "emoji": "🔴"}]
+}`
+
+	for _, tt := range []struct {
+		format ConfigFormat
+		input  string
+	}{
+		{ConfigYAML, sampleConfigYAML},
+		{ConfigJSON, jsonConfig},
+	} {
+		t.Run(string(tt.format), func(t *testing.T) {
+			config, err := ParseConfig(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("erro ao ler configuração: %v", err)
+			}
+			if want := sampleConfig(); !reflect.DeepEqual(config, want) {
+				t.Errorf("configuração = %+v\nesperado %+v", config, want)
+			}
+		})
+	}
+}
+
+// TestConfigDocExample garante que o exemplo do comentário de Config seja
+// aceito pelo parser de YAML
+func TestConfigDocExample(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "config.go", nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var example strings.Builder
+	ast.Inspect(file, func(n ast.Node) bool {
+		decl, ok := n.(*ast.GenDecl)
+		if !ok || decl.Doc == nil || len(decl.Specs) != 1 {
+			return true
+		}
+		if spec, ok := decl.Specs[0].(*ast.TypeSpec); ok && spec.Name.Name == "Config" {
+			for _, line := range strings.Split(decl.Doc.Text(), "\n") {
+				if text, ok := strings.CutPrefix(line, "\t"); ok {
+					example.WriteString(text + "\n")
+				}
+			}
+		}
+		return false
+	})
+	if example.Len() == 0 {
+		t.Fatal("exemplo não encontrado no comentário de Config")
+	}
+
+	config, err := ParseConfig(strings.NewReader(example.String()), ConfigYAML)
+	if err != nil {
+		t.Fatalf("exemplo inválido: %v\n%s", err, example.String())
+	}
+	if len(config.Buckets) != 3 || config.Buckets[0].Label != "Excelente" || config.Thresholds.Total != 80 {
+		t.Errorf("exemplo lido incorretamente: %+v", config)
+	}
+}
+
+func TestConfigOptions(t *testing.T) {
+	config := sampleConfig()
+	if got := config.ParseOptions().Validation; got != ValidateStrict {
+		t.Errorf("validação = %v, esperado ValidateStrict", got)
+	}
+	html, err := config.HTMLOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html.Title != "Cobertura do app" {
+		t.Errorf("título = %q", html.Title)
+	}
+	if len(html.Buckets) != 2 || html.Buckets[0].Name != "top" {
+		t.Errorf("faixas = %+v", html.Buckets)
+	}
+	if html.Language != LanguageEnglish {
+		t.Errorf("idioma do HTML = %q, esperado en", html.Language)
+	}
+	if got := config.ParseOptions().Language; got != LanguageEnglish {
+		t.Errorf("idioma do parse = %q, esperado en", got)
+	}
+	if html.Templates == nil {
+		t.Error("diretório de templates não aplicado")
+	}
+	if root, _ := filepath.Abs("."); html.Sources == nil || html.Sources.Root != root {
+		t.Errorf("código-fonte = %+v, esperado resolver em %s", html.Sources, root)
+	}
+
+	config.Source = ""
+	if html, err := config.HTMLOptions(); err != nil || html.Sources != nil {
+		t.Errorf("sem source: resolver %+v, erro %v", html.Sources, err)
+	}
+
+	config.Validation = "lenient"
+	if got := config.ParseOptions().Validation; got != ValidateLenient {
+		t.Errorf("validação = %v, esperado ValidateLenient", got)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format ConfigFormat
+		input  string
+		want   string
+	}{
+		{"chave desconhecida", ConfigYAML, "titulo: x\n", `"titulo"`},
+		{"chave aninhada desconhecida", ConfigYAML, "outputs:\n  pdf: r.pdf\n", `"pdf"`},
+		{"chave desconhecida em JSON", ConfigJSON, `{"filters": {"skip": []}}`, `"skip"`},
+		{"tipo inválido", ConfigYAML, "inputs: coverage.out\n", "inputs"},
+		{"YAML inválido", ConfigYAML, "title: a\n  source: b\n", "linha 2"},
+		{"validação desconhecida", ConfigYAML, "validation: paranoid\n", "validation"},
+		{"patchMin fora da faixa", ConfigYAML, "patchMin: 120\n", "patchMin"},
+		{"precisão do badge", ConfigYAML, "badge:\n  precision: 9\n", "precision"},
+		{"diff sem base", ConfigYAML, "outputs:\n  diffMarkdown: diff.md\n", "base"},
+		{"idioma não suportado", ConfigYAML, "language: fr\n", "language"},
+		{"faixa inválida", ConfigYAML, "buckets:\n  - name: a\n    color: red\n", "buckets"},
+		{"regex inválida", ConfigYAML, "filters:\n  includeRegexp: ['(']\n", "regular"},
+		{"limite inválido", ConfigYAML, "thresholds:\n  total: 101\n", "101"},
+		{"formato desconhecido", ConfigFormat("toml"), "title = 'x'\n", "toml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig(strings.NewReader(tt.input), tt.format)
+			if err == nil {
+				t.Fatal("esperava erro")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("erro = %v, esperado mencionar %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	config, err := LoadConfig(write(".coverage-report.yml", sampleConfigYAML))
+	if err != nil {
+		t.Fatalf("erro ao ler YAML: %v", err)
+	}
+	if config.Title != "Cobertura do app" {
+		t.Errorf("título = %q", config.Title)
+	}
+
+	if _, err := LoadConfig(write("config.json", `{"title": "x"}`)); err != nil {
+		t.Errorf("erro ao ler JSON: %v", err)
+	}
+
+	if _, err := LoadConfig(write("config.toml", "title = 'x'\n")); err == nil {
+		t.Error("esperava erro para extensão não suportada")
+	}
+	if _, err := LoadConfig(filepath.Join(dir, "missing.yml")); err == nil {
+		t.Error("esperava erro para arquivo inexistente")
+	}
+	path := write("invalid.yaml", "title: [\n")
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("erro deveria citar o arquivo: %v", err)
+	}
+}
@@ -0,0 +1,466 @@
+package coverage
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidCoverData indica um arquivo de GOCOVERDIR corrompido ou em um
+// formato não suportado
+var ErrInvalidCoverData = errors.New("dados de cobertura inválidos")
+
+// Layout dos arquivos gravados em GOCOVERDIR por binários compilados com
+// -cover (ver internal/coverage no código-fonte do Go). Todos os campos
+// de tamanho fixo são little-endian.
+const (
+	covMetaPrefix     = "covmeta."
+	covCountersPrefix = "covcounters."
+
+	covMetaMagic    = "\x00\x63\x76\x6d" // \0cvm
+	covCounterMagic = "\x00\x63\x77\x6d" // \0cwm
+
+	covMetaFileVersion    = 1
+	covCounterFileVersion = 1
+
+	covMetaFileHeaderSize    = 56 // magic, versão, tamanho, pacotes, hash, string table, modo
+	covMetaPackageHeaderSize = 44 // tamanho, nome, caminho, módulo, hash, arquivos, funções
+	covCounterHeaderSize     = 32 // magic, versão, hash do meta, formato, endianness
+	covCounterFooterSize     = 16 // magic, número de segmentos
+)
+
+// Formatos dos contadores em um arquivo covcounters
+const (
+	covCountersRaw     = 1 // uint32 com endianness do cabeçalho
+	covCountersULEB128 = 2 // ULEB128
+)
+
+// covMetaFile é um arquivo covmeta decodificado: os pacotes de um binário
+// com as unidades cobríveis de cada função
+type covMetaFile struct {
+	hash     [16]byte
+	mode     string
+	packages [][]covFunc // pacote -> função
+}
+
+// covFunc é uma função instrumentada e suas unidades cobríveis. Os
+// contadores de um arquivo covcounters seguem a ordem de units.
+type covFunc struct {
+	file  string
+	units []CoverageBlock
+}
+
+// covFuncID identifica uma função pelo índice do pacote e da função no
+// arquivo covmeta
+type covFuncID struct {
+	pkg, fn uint32
+}
+
+// ReadCoverDir lê os arquivos covmeta.* e covcounters.* gravados em
+// GOCOVERDIR por binários compilados com go build -cover e os converte em
+// um ProjectCoverage, sem depender de go tool covdata. O resultado pode ser
+// combinado com perfis de go test via MergeProfiles, com a mesma semântica
+// de contadores.
+//
+// Funções sem contadores (não executadas) entram com contagem zero. Um
+// diretório sem arquivos covmeta retorna erro; arquivos corrompidos
+// retornam um erro com ErrInvalidCoverData.
+func ReadCoverDir(dir string) (*ProjectCoverage, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler GOCOVERDIR: %w", err)
+	}
+
+	var metaNames, counterNames []string
+	for _, entry := range entries {
+		switch name := entry.Name(); {
+		case entry.IsDir():
+		case strings.HasPrefix(name, covMetaPrefix):
+			metaNames = append(metaNames, name)
+		case strings.HasPrefix(name, covCountersPrefix):
+			counterNames = append(counterNames, name)
+		}
+	}
+	if len(metaNames) == 0 {
+		return nil, fmt.Errorf("nenhum arquivo %s* encontrado em %s", covMetaPrefix, dir)
+	}
+
+	// Ordem determinística para que o resultado e os erros sejam estáveis
+	sort.Strings(metaNames)
+	ordered := make([]*covMetaFile, 0, len(metaNames))
+	metas := make(map[[16]byte]*covMetaFile, len(metaNames))
+	for _, name := range metaNames {
+		meta, err := readCovMetaFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		ordered = append(ordered, meta)
+		metas[meta.hash] = meta
+	}
+
+	counters := make(map[[16]byte]map[covFuncID][]int, len(metas))
+	for _, name := range counterNames {
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler arquivo de contadores: %w", err)
+		}
+		hash, err := covCountersMetaHash(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		meta, ok := metas[hash]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w: arquivo %s%s ausente", path, ErrInvalidCoverData, covMetaPrefix, hex.EncodeToString(hash[:]))
+		}
+		if counters[hash] == nil {
+			counters[hash] = make(map[covFuncID][]int)
+		}
+		if err := readCovCounters(data, meta, counters[hash]); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	builder := newProfileBuilder("")
+	for i, meta := range ordered {
+		if err := addCovMeta(builder, meta, counters[meta.hash]); err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Join(dir, metaNames[i]), err)
+		}
+	}
+	return builder.finish(), nil
+}
+
+// addCovMeta adiciona ao builder todas as unidades do arquivo covmeta com
+// os contadores acumulados dos arquivos covcounters correspondentes
+func addCovMeta(builder *profileBuilder, meta *covMetaFile, counters map[covFuncID][]int) error {
+	if err := builder.setMode(meta.mode); err != nil {
+		return err
+	}
+	for pkg, funcs := range meta.packages {
+		for fn, f := range funcs {
+			values := counters[covFuncID{uint32(pkg), uint32(fn)}]
+			for i, unit := range f.units {
+				if i < len(values) {
+					unit.Count = values[i]
+				}
+				if err := builder.add(f.file, unit); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// readCovMetaFile decodifica um arquivo covmeta
+func readCovMetaFile(path string) (*covMetaFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de metadados: %w", err)
+	}
+	meta, err := decodeCovMeta(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return meta, nil
+}
+
+func decodeCovMeta(data []byte) (*covMetaFile, error) {
+	r := &covReader{data: data}
+	if r.string(4) != covMetaMagic {
+		return nil, fmt.Errorf("%w: não é um arquivo covmeta", ErrInvalidCoverData)
+	}
+	if version := r.uint32(); version > covMetaFileVersion {
+		return nil, fmt.Errorf("%w: versão %d do arquivo covmeta não suportada", ErrInvalidCoverData, version)
+	}
+	r.skip(8) // tamanho total
+	numPackages := r.uint64()
+
+	meta := &covMetaFile{}
+	copy(meta.hash[:], r.bytes(16))
+	r.skip(8) // string table do arquivo, não usada
+
+	switch mode := r.uint8(); mode {
+	case 1:
+		meta.mode = ModeSet
+	case 2:
+		meta.mode = ModeCount
+	case 3:
+		meta.mode = ModeAtomic
+	default:
+		if r.err == nil {
+			return nil, fmt.Errorf("%w: modo de contador %d desconhecido", ErrInvalidCoverData, mode)
+		}
+	}
+	r.seek(covMetaFileHeaderSize)
+
+	// Cada pacote tem um offset e um tamanho de 8 bytes
+	if r.err != nil || numPackages > uint64(len(data)/16) {
+		return nil, fmt.Errorf("%w: cabeçalho do arquivo covmeta truncado", ErrInvalidCoverData)
+	}
+	offsets := make([]uint64, numPackages)
+	for i := range offsets {
+		offsets[i] = r.uint64()
+	}
+	meta.packages = make([][]covFunc, numPackages)
+	for i := range meta.packages {
+		offset, length := offsets[i], r.uint64()
+		if offset > uint64(len(data)) || length > uint64(len(data))-offset {
+			return nil, fmt.Errorf("%w: pacote %d fora do arquivo covmeta", ErrInvalidCoverData, i)
+		}
+		funcs, err := decodeCovMetaPackage(data[offset : offset+length])
+		if err != nil {
+			return nil, fmt.Errorf("pacote %d: %w", i, err)
+		}
+		meta.packages[i] = funcs
+	}
+	return meta, nil
+}
+
+// decodeCovMetaPackage decodifica as funções de um pacote do arquivo covmeta
+func decodeCovMetaPackage(data []byte) ([]covFunc, error) {
+	r := &covReader{data: data}
+	r.seek(covMetaPackageHeaderSize - 4)
+	numFuncs := r.uint32()
+	if r.err != nil || uint64(numFuncs)*4 > uint64(len(data)) {
+		return nil, fmt.Errorf("%w: cabeçalho do pacote truncado", ErrInvalidCoverData)
+	}
+
+	offsets := make([]uint32, numFuncs)
+	for i := range offsets {
+		offsets[i] = r.uint32()
+	}
+	table := r.stringTable()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	funcs := make([]covFunc, numFuncs)
+	for i, offset := range offsets {
+		r.seek(int(offset))
+		numUnits := r.uleb128()
+		r.uleb128() // nome da função
+		file := r.index(table)
+		if numUnits > uint64(r.remaining()) {
+			return nil, fmt.Errorf("%w: função %d com %d unidades", ErrInvalidCoverData, i, numUnits)
+		}
+
+		units := make([]CoverageBlock, numUnits)
+		for j := range units {
+			units[j] = CoverageBlock{
+				StartLine: int(r.uleb128()),
+				StartCol:  int(r.uleb128()),
+				EndLine:   int(r.uleb128()),
+				EndCol:    int(r.uleb128()),
+				NumStmt:   int(r.uleb128()),
+			}
+		}
+		r.uleb128() // literal de função
+		if r.err != nil {
+			return nil, fmt.Errorf("função %d: %w", i, r.err)
+		}
+		funcs[i] = covFunc{file: file, units: units}
+	}
+	return funcs, nil
+}
+
+// covCountersMetaHash valida o cabeçalho de um arquivo covcounters e
+// retorna o hash do arquivo covmeta correspondente
+func covCountersMetaHash(data []byte) ([16]byte, error) {
+	var hash [16]byte
+	r := &covReader{data: data}
+	if r.string(4) != covCounterMagic {
+		return hash, fmt.Errorf("%w: não é um arquivo covcounters", ErrInvalidCoverData)
+	}
+	if version := r.uint32(); version > covCounterFileVersion {
+		return hash, fmt.Errorf("%w: versão %d do arquivo covcounters não suportada", ErrInvalidCoverData, version)
+	}
+	copy(hash[:], r.bytes(16))
+	return hash, r.err
+}
+
+// readCovCounters decodifica os segmentos de um arquivo covcounters,
+// acumulando os contadores de cada função em counters
+func readCovCounters(data []byte, meta *covMetaFile, counters map[covFuncID][]int) error {
+	r := &covReader{data: data}
+	r.seek(24) // magic, versão e hash já validados
+	flavor := r.uint8()
+	bigEndian := r.uint8() != 0
+
+	r.seek(len(data) - covCounterFooterSize)
+	if r.string(4) != covCounterMagic {
+		return fmt.Errorf("%w: rodapé do arquivo covcounters inválido", ErrInvalidCoverData)
+	}
+	r.skip(4)
+	numSegments := r.uint32()
+
+	var value func() uint64
+	switch flavor {
+	case covCountersRaw:
+		order := binary.ByteOrder(binary.LittleEndian)
+		if bigEndian {
+			order = binary.BigEndian
+		}
+		value = func() uint64 { return uint64(r.uint32With(order)) }
+	case covCountersULEB128:
+		value = r.uleb128
+	default:
+		return fmt.Errorf("%w: formato de contador %d desconhecido", ErrInvalidCoverData, flavor)
+	}
+
+	r.seek(covCounterHeaderSize)
+	for segment := uint32(0); segment < numSegments && r.err == nil; segment++ {
+		numFuncs := r.uint64()
+		stringsLen := r.uint32()
+		argsLen := r.uint32()
+		r.skip(int(stringsLen) + int(argsLen)) // argumentos do processo, não usados
+		r.align(4)
+
+		for i := uint64(0); i < numFuncs && r.err == nil; i++ {
+			numCounters := value()
+			id := covFuncID{uint32(value()), uint32(value())}
+			if numCounters > uint64(r.remaining()) {
+				return fmt.Errorf("%w: função com %d contadores", ErrInvalidCoverData, numCounters)
+			}
+			if int(id.pkg) >= len(meta.packages) || int(id.fn) >= len(meta.packages[id.pkg]) {
+				return fmt.Errorf("%w: função %d do pacote %d não existe no arquivo covmeta", ErrInvalidCoverData, id.fn, id.pkg)
+			}
+
+			merged := counters[id]
+			for j := 0; j < int(numCounters); j++ {
+				count := int(value())
+				if j < len(merged) {
+					merged[j] = mergeCounts(meta.mode, merged[j], count)
+				} else {
+					merged = append(merged, count)
+				}
+			}
+			counters[id] = merged
+		}
+		r.skip(covCounterFooterSize) // cada segmento termina com um rodapé
+	}
+	return r.err
+}
+
+// covReader lê os campos de um arquivo de GOCOVERDIR. O primeiro acesso
+// fora dos limites é registrado em err e as leituras seguintes retornam
+// zero.
+type covReader struct {
+	data []byte
+	off  int
+	err  error
+}
+
+func (r *covReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data)-r.off {
+		r.err = fmt.Errorf("%w: arquivo truncado no byte %d", ErrInvalidCoverData, r.off)
+		return nil
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+func (r *covReader) string(n int) string { return string(r.bytes(n)) }
+
+func (r *covReader) skip(n int) { r.bytes(n) }
+
+func (r *covReader) remaining() int { return len(r.data) - r.off }
+
+func (r *covReader) seek(off int) {
+	if r.err == nil && (off < 0 || off > len(r.data)) {
+		r.err = fmt.Errorf("%w: offset %d fora do arquivo", ErrInvalidCoverData, off)
+		return
+	}
+	r.off = off
+}
+
+// align avança até o próximo múltiplo de n
+func (r *covReader) align(n int) {
+	if rem := r.off % n; rem != 0 {
+		r.skip(n - rem)
+	}
+}
+
+func (r *covReader) uint8() uint8 {
+	if b := r.bytes(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *covReader) uint32() uint32 {
+	return r.uint32With(binary.LittleEndian)
+}
+
+func (r *covReader) uint32With(order binary.ByteOrder) uint32 {
+	if b := r.bytes(4); b != nil {
+		return order.Uint32(b)
+	}
+	return 0
+}
+
+func (r *covReader) uint64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
+	}
+	return 0
+}
+
+func (r *covReader) uleb128() uint64 {
+	var value uint64
+	for shift := uint(0); ; shift += 7 {
+		b := r.bytes(1)
+		if b == nil {
+			return 0
+		}
+		if shift >= 64 {
+			r.err = fmt.Errorf("%w: ULEB128 longo demais no byte %d", ErrInvalidCoverData, r.off)
+			return 0
+		}
+		value |= uint64(b[0]&0x7f) << shift
+		if b[0]&0x80 == 0 {
+			return value
+		}
+	}
+}
+
+// stringTable lê uma string table: a quantidade e, para cada string, o
+// tamanho e os bytes
+func (r *covReader) stringTable() []string {
+	n := r.uleb128()
+	if n > uint64(r.remaining()) {
+		r.err = fmt.Errorf("%w: string table com %d entradas", ErrInvalidCoverData, n)
+		return nil
+	}
+	table := make([]string, 0, n)
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		length := r.uleb128()
+		if length > uint64(r.remaining()) {
+			r.err = fmt.Errorf("%w: string com %d bytes", ErrInvalidCoverData, length)
+			return nil
+		}
+		table = append(table, r.string(int(length)))
+	}
+	return table
+}
+
+// index lê um índice ULEB128 e retorna a string correspondente da tabela
+func (r *covReader) index(table []string) string {
+	i := r.uleb128()
+	if r.err == nil && i >= uint64(len(table)) {
+		r.err = fmt.Errorf("%w: índice %d fora da string table", ErrInvalidCoverData, i)
+	}
+	if r.err != nil {
+		return ""
+	}
+	return table[i]
+}
@@ -0,0 +1,258 @@
+package coverage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testdata/coverdir foi gravado por um binário de exemplo compilado com
+// go build -cover -covermode=count e executado duas vezes
+const coverdirProfile = `mode: count
+example.com/demo/main.go:11.2,12.1 1 2
+example.com/demo/calc/calc.go:4.2,4.11 1 2
+example.com/demo/calc/calc.go:5.3,6.1 1 0
+example.com/demo/calc/calc.go:7.2,7.14 1 2
+example.com/demo/calc/calc.go:11.2,11.18 1 0
+example.com/demo/calc/calc.go:11.20,11.30 1 0
+example.com/demo/calc/calc.go:12.2,12.12 1 0
+`
+
+func TestReadCoverDir(t *testing.T) {
+	cov, err := ReadCoverDir(filepath.Join("testdata", "coverdir"))
+	if err != nil {
+		t.Fatalf("erro ao ler GOCOVERDIR: %v", err)
+	}
+
+	want := mustParse(t, coverdirProfile)
+	if !reflect.DeepEqual(cov, want) {
+		t.Errorf("cobertura = %+v\nesperado %+v", cov, want)
+	}
+}
+
+func TestReadCoverDirMergesWithProfiles(t *testing.T) {
+	cov, err := ReadCoverDir(filepath.Join("testdata", "coverdir"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unit := mustParse(t, "mode: count\nexample.com/demo/calc/calc.go:5.3,6.1 1 3\n")
+
+	merged, err := MergeProfiles(unit, cov)
+	if err != nil {
+		t.Fatalf("erro ao combinar: %v", err)
+	}
+	file := merged.Files["example.com/demo/calc/calc.go"]
+	if file.Blocks[1].Count != 3 || file.CoveredStmt != 3 || file.TotalStmt != 6 {
+		t.Errorf("calc.go = %+v", file)
+	}
+
+	set := mustParse(t, "mode: set\nexample.com/demo/main.go:11.2,12.1 1 1\n")
+	if _, err := MergeProfiles(set, cov); !errors.Is(err, ErrMixedModes) {
+		t.Errorf("esperava ErrMixedModes, obteve %v", err)
+	}
+}
+
+func TestReadCoverDirErrors(t *testing.T) {
+	src := filepath.Join("testdata", "coverdir")
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// copyDir copia o diretório de exemplo aplicando edit ao conteúdo de
+	// cada arquivo; edit retorna nil para omitir o arquivo
+	copyDir := func(t *testing.T, edit func(name string, data []byte) []byte) string {
+		t.Helper()
+		dir := t.TempDir()
+		for _, entry := range entries {
+			data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data = edit(entry.Name(), data); data != nil {
+				if err := os.WriteFile(filepath.Join(dir, entry.Name()), data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		return dir
+	}
+
+	tests := []struct {
+		name    string
+		edit    func(name string, data []byte) []byte
+		wantErr error
+	}{
+		{
+			name: "sem covmeta",
+			edit: func(name string, data []byte) []byte {
+				if strings.HasPrefix(name, covMetaPrefix) {
+					return nil
+				}
+				return data
+			},
+		},
+		{
+			name: "magic inválido",
+			edit: func(name string, data []byte) []byte {
+				if strings.HasPrefix(name, covMetaPrefix) {
+					return append([]byte("xxxx"), data[4:]...)
+				}
+				return data
+			},
+			wantErr: ErrInvalidCoverData,
+		},
+		{
+			name: "versão futura",
+			edit: func(name string, data []byte) []byte {
+				if strings.HasPrefix(name, covCountersPrefix) {
+					data = bytes.Clone(data)
+					data[4] = 9
+				}
+				return data
+			},
+			wantErr: ErrInvalidCoverData,
+		},
+		{
+			name: "covmeta truncado",
+			edit: func(name string, data []byte) []byte {
+				if strings.HasPrefix(name, covMetaPrefix) {
+					return data[:len(data)/2]
+				}
+				return data
+			},
+			wantErr: ErrInvalidCoverData,
+		},
+		{
+			name: "covcounters truncado",
+			edit: func(name string, data []byte) []byte {
+				if strings.HasPrefix(name, covCountersPrefix) {
+					return data[:40]
+				}
+				return data
+			},
+			wantErr: ErrInvalidCoverData,
+		},
+		{
+			name: "covmeta de outro binário",
+			edit: func(name string, data []byte) []byte {
+				if strings.HasPrefix(name, covCountersPrefix) {
+					data = bytes.Clone(data)
+					data[8] ^= 0xff
+				}
+				return data
+			},
+			wantErr: ErrInvalidCoverData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCoverDir(copyDir(t, tt.edit))
+			if err == nil {
+				t.Fatal("esperava erro")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("erro = %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := ReadCoverDir(filepath.Join("testdata", "inexistente")); err == nil {
+		t.Error("esperava erro para diretório inexistente")
+	}
+}
+
+// FuzzReadCoverDir garante que arquivos corrompidos resultem em erro, e não
+// em pânico ou alocações desproporcionais
+func FuzzReadCoverDir(f *testing.F) {
+	src := filepath.Join("testdata", "coverdir")
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		f.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			f.Fatal(err)
+		}
+		files[entry.Name()] = data
+		f.Add(entry.Name(), data)
+	}
+
+	f.Fuzz(func(t *testing.T, name string, data []byte) {
+		if _, ok := files[name]; !ok {
+			return
+		}
+		dir := t.TempDir()
+		for other, content := range files {
+			if other == name {
+				content = data
+			}
+			if err := os.WriteFile(filepath.Join(dir, other), content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ReadCoverDir(dir)
+	})
+}
+
+// TestReadCoverDirMatchesCovdata compila um binário com -cover e compara o
+// resultado com go tool covdata textfmt
+func TestReadCoverDirMatchesCovdata(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compila um binário com go build -cover")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go não encontrado no PATH")
+	}
+
+	root := writeTestModule(t)
+	mainSrc := "package main\n\nimport (\n\t\"os\"\n\n\t\"example.com/demo/pkg/calc\"\n)\n\nfunc main() {\n\tos.Exit(calc.Sum(len(os.Args)-2, 0))\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(mainSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mode := range []string{ModeSet, ModeAtomic} {
+		t.Run(mode, func(t *testing.T) {
+			bin := filepath.Join(t.TempDir(), "demo")
+			build := exec.Command(goTool, "build", "-cover", "-covermode="+mode, "-o", bin, ".")
+			build.Dir = root
+			if out, err := build.CombinedOutput(); err != nil {
+				t.Fatalf("go build -cover: %v\n%s", err, out)
+			}
+
+			dir := t.TempDir()
+			for _, args := range [][]string{{}, {"a"}, {"a", "b"}} {
+				cmd := exec.Command(bin, args...)
+				cmd.Env = append(os.Environ(), "GOCOVERDIR="+dir)
+				cmd.Run() // o código de saída varia com os argumentos
+			}
+
+			textfmt := filepath.Join(t.TempDir(), "coverage.out")
+			covdata := exec.Command(goTool, "tool", "covdata", "textfmt", "-i="+dir, "-o="+textfmt)
+			if out, err := covdata.CombinedOutput(); err != nil {
+				t.Skipf("go tool covdata indisponível: %v\n%s", err, out)
+			}
+			data, err := os.ReadFile(textfmt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadCoverDir(dir)
+			if err != nil {
+				t.Fatalf("erro ao ler GOCOVERDIR: %v", err)
+			}
+			if want := mustParse(t, string(data)); !reflect.DeepEqual(got, want) {
+				t.Errorf("cobertura = %+v\nesperado (covdata) %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package coverage
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// IgnoreDirective é o comentário que exclui código do relatório
+const IgnoreDirective = "//coverage:ignore"
+
+// FilterOptions define quais arquivos e blocos entram no relatório.
+//
+// Os padrões glob aceitam *, ? e ** e, quando não contêm "/", são
+// comparados apenas com o nome base (ex.: "*.pb.go"). As expressões
+// regulares são comparadas com o caminho completo do perfil, sem âncoras
+// implícitas. Um arquivo é mantido se casa com alguma regra de inclusão
+// (ou se não há nenhuma) e não casa com nenhuma regra de exclusão.
+type FilterOptions struct {
+	Include       []string `json:"include,omitempty"`       // globs de arquivos incluídos
+	Exclude       []string `json:"exclude,omitempty"`       // globs de arquivos excluídos (ex.: "**/vendor/**")
+	IncludeRegexp []string `json:"includeRegexp,omitempty"` // expressões regulares de arquivos incluídos
+	ExcludeRegexp []string `json:"excludeRegexp,omitempty"` // expressões regulares de arquivos excluídos
+
+	// Sources habilita os filtros que dependem do código-fonte: arquivos
+	// com o cabeçalho "// Code generated ... DO NOT EDIT." e código marcado
+	// com IgnoreDirective. Arquivos não encontrados são mantidos.
+	Sources *SourceResolver `json:"-"`
+	// KeepGenerated mantém os arquivos gerados mesmo com Sources
+	KeepGenerated bool `json:"keepGenerated,omitempty"`
+	// KeepIgnored desconsidera os comentários IgnoreDirective
+	KeepIgnored bool `json:"keepIgnored,omitempty"`
+	// OnWarning recebe os arquivos cujo código não pôde ser analisado; eles
+	// são mantidos sem os filtros do código-fonte. É chamado na ordem dos
+	// caminhos.
+	OnWarning func(filePath string, err error) `json:"-"`
+}
+
+// FilterCoverage retorna uma cópia da cobertura apenas com os arquivos e
+// blocos selecionados por opts, com os totais recalculados. A cobertura de
+// entrada não é alterada.
+//
+// Com Sources, um comentário IgnoreDirective exclui a instrução ou função
+// que começa na mesma linha do comentário ou, se o comentário estiver
+// sozinho na linha, logo após o bloco de comentários (ex.: na documentação
+// de uma função). Antes da cláusula package, ele exclui o arquivo inteiro.
+// Arquivos cujos blocos foram todos ignorados são removidos. Apenas blocos
+// do perfil inteiramente dentro do nó marcado são ignorados: um bloco que
+// também cobre código fora dele (ex.: uma sequência de instruções simples em
+// que só uma foi marcada) é mantido, pois o perfil não divide os statements
+// de um bloco.
+func FilterCoverage(coverage *ProjectCoverage, opts FilterOptions) (*ProjectCoverage, error) {
+	matcher, err := newPathFilter(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := &ProjectCoverage{
+		Mode:  coverage.Mode,
+		Files: make(map[string]*FileCoverage, len(coverage.Files)),
+	}
+	for _, file := range coverage.GetSortedFiles() {
+		filePath := file.FilePath
+		if !matcher.match(filePath) {
+			continue
+		}
+
+		blocks := append([]CoverageBlock(nil), file.Blocks...)
+		if opts.Sources != nil && (!opts.KeepGenerated || !opts.KeepIgnored) {
+			if src, err := opts.Sources.ReadSource(filePath); err == nil {
+				info, err := analyzeFilterSource(filePath, src)
+				if err != nil {
+					// Código que não compila não impede o filtro dos demais
+					if opts.OnWarning != nil {
+						opts.OnWarning(filePath, err)
+					}
+					info = &filterSource{}
+				}
+				if info.generated && !opts.KeepGenerated {
+					continue
+				}
+				if !opts.KeepIgnored {
+					blocks = info.removeIgnored(blocks)
+					if len(blocks) == 0 && len(file.Blocks) > 0 {
+						continue
+					}
+				}
+			}
+		}
+
+		copied := &FileCoverage{
+			FilePath: file.FilePath,
+			FileName: file.FileName,
+			Blocks:   blocks,
+		}
+		copied.recalculate()
+		filtered.Files[filePath] = copied
+	}
+	return filtered, nil
+}
+
+// pathFilter aplica as regras de inclusão e exclusão aos caminhos
+type pathFilter struct {
+	include, exclude []func(string) bool
+}
+
+func newPathFilter(opts FilterOptions) (*pathFilter, error) {
+	pf := &pathFilter{}
+	add := func(rules *[]func(string) bool, globs, regexps []string) error {
+		for _, pattern := range globs {
+			glob, err := compileGlob(pattern)
+			if err != nil {
+				return err
+			}
+			*rules = append(*rules, glob.Match)
+		}
+		for _, expr := range regexps {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("expressão regular inválida %q: %w", expr, err)
+			}
+			*rules = append(*rules, re.MatchString)
+		}
+		return nil
+	}
+
+	if err := add(&pf.include, opts.Include, opts.IncludeRegexp); err != nil {
+		return nil, err
+	}
+	if err := add(&pf.exclude, opts.Exclude, opts.ExcludeRegexp); err != nil {
+		return nil, err
+	}
+	return pf, nil
+}
+
+// match indica se o arquivo deve ser mantido
+func (pf *pathFilter) match(filePath string) bool {
+	included := len(pf.include) == 0
+	for _, rule := range pf.include {
+		if rule(filePath) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+	for _, rule := range pf.exclude {
+		if rule(filePath) {
+			return false
+		}
+	}
+	return true
+}
+
+// sourceRange é um intervalo linha.coluna do código-fonte; a coluna final
+// é exclusiva, como nos blocos do perfil
+type sourceRange struct {
+	StartLine, StartCol, EndLine, EndCol int
+}
+
+// contains indica se o bloco está inteiramente dentro do intervalo
+func (r sourceRange) contains(b CoverageBlock) bool {
+	startsAfter := b.StartLine > r.StartLine || (b.StartLine == r.StartLine && b.StartCol >= r.StartCol)
+	endsBefore := b.EndLine < r.EndLine || (b.EndLine == r.EndLine && b.EndCol <= r.EndCol)
+	return startsAfter && endsBefore
+}
+
+// filterSource é o resultado da análise do código de um arquivo
+type filterSource struct {
+	generated bool
+	ignored   []sourceRange
+}
+
+// removeIgnored retorna os blocos fora dos intervalos ignorados
+func (fs *filterSource) removeIgnored(blocks []CoverageBlock) []CoverageBlock {
+	kept := blocks[:0]
+	for _, block := range blocks {
+		ignored := false
+		for _, r := range fs.ignored {
+			if r.contains(block) {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			kept = append(kept, block)
+		}
+	}
+	return kept
+}
+
+// analyzeFilterSource detecta o cabeçalho de código gerado e os intervalos
+// marcados com IgnoreDirective
+func analyzeFilterSource(filePath string, src []byte) (*filterSource, error) {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, filePath, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao analisar %s: %w", filePath, err)
+	}
+
+	info := &filterSource{generated: ast.IsGenerated(parsed)}
+	lines := strings.Split(string(src), "\n")
+
+	for _, group := range parsed.Comments {
+		for _, comment := range group.List {
+			if !isIgnoreDirective(comment.Text) {
+				continue
+			}
+			if comment.Pos() < parsed.Package {
+				// O arquivo inteiro é ignorado
+				info.ignored = []sourceRange{{1, 1, len(lines) + 1, 1}}
+				return info, nil
+			}
+
+			// Comentário sozinho na linha vale para a linha após o grupo
+			// de comentários (ex.: a documentação de uma função)
+			pos := fset.Position(comment.Pos())
+			line := pos.Line
+			if strings.TrimSpace(lines[line-1][:pos.Column-1]) == "" {
+				line = fset.Position(group.End()).Line + 1
+			}
+			if r, ok := nodeStartingAt(fset, parsed, line); ok {
+				info.ignored = append(info.ignored, r)
+			}
+		}
+	}
+	return info, nil
+}
+
+// isIgnoreDirective indica se o comentário é IgnoreDirective, opcionalmente
+// seguido de uma justificativa (ex.: "//coverage:ignore inalcançável")
+func isIgnoreDirective(text string) bool {
+	rest, ok := strings.CutPrefix(text, IgnoreDirective)
+	return ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t')
+}
+
+// nodeStartingAt retorna o intervalo da primeira declaração de função ou
+// instrução que começa na linha informada; entre nós que começam na mesma
+// posição vale o mais externo
+func nodeStartingAt(fset *token.FileSet, file *ast.File, line int) (sourceRange, bool) {
+	var found ast.Node
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncDecl, ast.Stmt:
+		default:
+			return n != nil
+		}
+		if fset.Position(n.Pos()).Line == line && (found == nil || n.Pos() < found.Pos()) {
+			found = n
+		}
+		return true
+	})
+	if found == nil {
+		return sourceRange{}, false
+	}
+
+	start, end := fset.Position(found.Pos()), fset.Position(found.End())
+	return sourceRange{start.Line, start.Column, end.Line, end.Column}, true
+}
@@ -0,0 +1,249 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func sortedPaths(cov *ProjectCoverage) []string {
+	paths := make([]string, 0, len(cov.Files))
+	for path := range cov.Files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func TestFilterCoveragePatterns(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/app/api/api.pb.go:1.1,2.2 4 0
+example.com/app/api/handler.go:1.1,2.2 2 1
+example.com/app/internal/mocks/store.go:1.1,2.2 3 0
+example.com/app/vendor/lib/lib.go:1.1,2.2 5 0
+example.com/app/zz_generated.deepcopy.go:1.1,2.2 6 0
+example.com/app/main.go:1.1,2.2 2 0
+`)
+
+	tests := []struct {
+		name string
+		opts FilterOptions
+		want []string
+	}{
+		{
+			name: "sem regras",
+			want: sortedPaths(cov),
+		},
+		{
+			name: "exclusão por glob",
+			opts: FilterOptions{Exclude: []string{"*.pb.go", "zz_generated*", "**/vendor/**"}},
+			want: []string{"example.com/app/api/handler.go", "example.com/app/internal/mocks/store.go", "example.com/app/main.go"},
+		},
+		{
+			name: "exclusão por regex",
+			opts: FilterOptions{ExcludeRegexp: []string{`/mocks/`, `\.pb\.go$`}},
+			want: []string{"example.com/app/api/handler.go", "example.com/app/main.go", "example.com/app/vendor/lib/lib.go", "example.com/app/zz_generated.deepcopy.go"},
+		},
+		{
+			name: "inclusão com exclusão",
+			opts: FilterOptions{Include: []string{"example.com/app/api/**"}, IncludeRegexp: []string{`main\.go$`}, Exclude: []string{"*.pb.go"}},
+			want: []string{"example.com/app/api/handler.go", "example.com/app/main.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := FilterCoverage(cov, tt.opts)
+			if err != nil {
+				t.Fatalf("erro ao filtrar: %v", err)
+			}
+			if got := sortedPaths(filtered); !slices.Equal(got, tt.want) {
+				t.Errorf("arquivos = %v, esperado %v", got, tt.want)
+			}
+		})
+	}
+
+	filtered, err := FilterCoverage(cov, FilterOptions{Exclude: []string{"*.pb.go", "zz_generated*", "**/vendor/**", "**/mocks/**"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := filtered.GetTotalCoverage(); total != 50 {
+		t.Errorf("cobertura total após o filtro = %.1f, esperado 50", total)
+	}
+	if len(cov.Files) != 6 {
+		t.Error("a cobertura de entrada foi alterada")
+	}
+}
+
+func TestFilterCoverageNonASCIIPatterns(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/app/ação/ação.go:1.1,2.2 1 1
+example.com/app/ação/açúcar_gen.go:1.1,2.2 1 0
+example.com/app/main.go:1.1,2.2 1 0
+`)
+
+	filtered, err := FilterCoverage(cov, FilterOptions{Include: []string{"example.com/app/ação/*.go"}, Exclude: []string{"açúcar_*.go"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := sortedPaths(filtered), []string{"example.com/app/ação/ação.go"}; !slices.Equal(got, want) {
+		t.Errorf("arquivos = %v, esperado %v", got, want)
+	}
+}
+
+func TestFilterCoverageInvalidPatterns(t *testing.T) {
+	cov := mustParse(t, "mode: set\npkg/a.go:1.1,2.2 1 1\n")
+
+	for _, opts := range []FilterOptions{
+		{Include: []string{""}},
+		{ExcludeRegexp: []string{"("}},
+	} {
+		if _, err := FilterCoverage(cov, opts); err == nil {
+			t.Errorf("esperava erro para %+v", opts)
+		}
+	}
+}
+
+const filterSourceCode = `package calc
+
+// Div divide a por b.
+func Div(a, b int) int {
+	if b == 0 { //coverage:ignore
+		panic("divisão por zero")
+	}
+	return a / b
+}
+
+//coverage:ignore depuração
+// Debug é usado apenas em desenvolvimento.
+func Debug() string {
+	return "debug"
+}
+`
+
+func TestFilterCoverageSources(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":           "module example.com/demo\n\ngo 1.24\n",
+		"calc/calc.go":     filterSourceCode,
+		"calc/calc.pb.go":  "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage calc\n\nfunc Proto() int {\n\treturn 1\n}\n",
+		"calc/legacy.go":   "//coverage:ignore código legado\n\npackage calc\n\nfunc Legacy() int {\n\treturn 1\n}\n",
+		"calc/coverage.go": "package calc\n\n//coverage:ignored não é a diretiva\nfunc Other() int {\n\treturn 1\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sources, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cov := mustParse(t, `mode: set
+example.com/demo/calc/calc.go:5.2,5.12 1 1
+example.com/demo/calc/calc.go:6.3,6.29 1 0
+example.com/demo/calc/calc.go:8.2,8.14 1 1
+example.com/demo/calc/calc.go:14.2,15.1 1 0
+example.com/demo/calc/calc.pb.go:6.2,6.10 1 0
+example.com/demo/calc/legacy.go:6.2,6.10 1 0
+example.com/demo/calc/coverage.go:5.2,5.10 1 0
+example.com/demo/calc/missing.go:1.1,2.2 1 0
+`)
+
+	filtered, err := FilterCoverage(cov, FilterOptions{Sources: sources})
+	if err != nil {
+		t.Fatalf("erro ao filtrar: %v", err)
+	}
+	want := []string{"example.com/demo/calc/calc.go", "example.com/demo/calc/coverage.go", "example.com/demo/calc/missing.go"}
+	if got := sortedPaths(filtered); !slices.Equal(got, want) {
+		t.Errorf("arquivos = %v, esperado %v", got, want)
+	}
+
+	calc := filtered.Files["example.com/demo/calc/calc.go"]
+	if len(calc.Blocks) != 1 || calc.Blocks[0].StartLine != 8 {
+		t.Errorf("blocos de calc.go = %+v, esperado apenas o da linha 8", calc.Blocks)
+	}
+	if calc.TotalStmt != 1 || calc.Coverage != 100 {
+		t.Errorf("calc.go = %d statements, %.1f%%, esperado 1 e 100%%", calc.TotalStmt, calc.Coverage)
+	}
+	if len(cov.Files["example.com/demo/calc/calc.go"].Blocks) != 4 {
+		t.Error("a cobertura de entrada foi alterada")
+	}
+
+	kept, err := FilterCoverage(cov, FilterOptions{Sources: sources, KeepGenerated: true, KeepIgnored: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(kept.Files) != len(cov.Files) || len(kept.Files["example.com/demo/calc/calc.go"].Blocks) != 4 {
+		t.Errorf("KeepGenerated e KeepIgnored não deveriam remover nada: %v", sortedPaths(kept))
+	}
+}
+
+func TestFilterCoverageInvalidSource(t *testing.T) {
+	root := writeTestModule(t)
+	for name, content := range map[string]string{
+		"sum.go":       "package calc\n\nfunc {\n",
+		"generated.go": "// Code generated by hand. DO NOT EDIT.\n\npackage calc\n\nfunc Gen() int {\n\treturn 1\n}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(root, "pkg", "calc", name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sources, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cov := mustParse(t, `mode: set
+example.com/demo/pkg/calc/generated.go:6.2,6.10 1 0
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 1
+`)
+	var warned []string
+	filtered, err := FilterCoverage(cov, FilterOptions{
+		Sources: sources,
+		OnWarning: func(filePath string, err error) {
+			if !strings.Contains(err.Error(), "sum.go") {
+				t.Errorf("aviso de %s sem o arquivo: %v", filePath, err)
+			}
+			warned = append(warned, filePath)
+		},
+	})
+	if err != nil {
+		t.Fatalf("código inválido não deveria impedir o filtro: %v", err)
+	}
+	if want := []string{"example.com/demo/pkg/calc/sum.go"}; !slices.Equal(warned, want) {
+		t.Errorf("avisos = %v, esperado %v", warned, want)
+	}
+	if got := sortedPaths(filtered); !slices.Equal(got, []string{"example.com/demo/pkg/calc/sum.go"}) {
+		t.Errorf("arquivos = %v, esperado apenas sum.go, sem os filtros do código", got)
+	}
+}
+
+func TestFilterCoverageIgnoreStraddlingBlock(t *testing.T) {
+	root := writeTestModule(t)
+	src := "package calc\n\nfunc Steps() int {\n\ta := 1\n\ta++ //coverage:ignore\n\treturn a\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "pkg", "calc", "steps.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sources, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// O bloco cobre as três instruções, não apenas a marcada
+	cov := mustParse(t, "mode: set\nexample.com/demo/pkg/calc/steps.go:3.18,6.10 3 0\n")
+	filtered, err := FilterCoverage(cov, FilterOptions{Sources: sources})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file := filtered.Files["example.com/demo/pkg/calc/steps.go"]; file == nil || len(file.Blocks) != 1 {
+		t.Errorf("bloco que atravessa o nó ignorado deveria ser mantido: %+v", file)
+	}
+}
@@ -0,0 +1,141 @@
+package coverage
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+)
+
+// FunctionCoverage é a cobertura de uma função ou método, no mesmo formato
+// de go tool cover -func
+type FunctionCoverage struct {
+	FilePath string
+	Name     string
+	// Receiver é o tipo do receptor (ex.: "*HTMLGenerator"); vazio para funções
+	Receiver  string
+	StartLine int
+	StartCol  int
+	EndLine   int
+	EndCol    int
+
+	TotalStmt   int
+	CoveredStmt int
+	Coverage    float64 // percentual de 0-100
+}
+
+// FullName retorna o nome qualificado pelo receptor: "(*T).Name" ou "Name"
+func (fc FunctionCoverage) FullName() string {
+	if fc.Receiver == "" {
+		return fc.Name
+	}
+	return fmt.Sprintf("(%s).%s", fc.Receiver, fc.Name)
+}
+
+// AnalyzeFunctions lê o código-fonte de cada arquivo do perfil e atribui os
+// blocos de cobertura às funções que os contêm. Arquivos cujo código não é
+// encontrado, ou todos quando sources é nil, são ignorados. O resultado é
+// ordenado por arquivo e linha.
+//
+// Um arquivo que não pode ser analisado não interrompe os demais: as funções
+// dos outros arquivos são retornadas junto com um erro que reúne (ver
+// errors.Join) o erro de cada arquivo ignorado.
+func AnalyzeFunctions(coverage *ProjectCoverage, sources *SourceResolver) ([]FunctionCoverage, error) {
+	functions := []FunctionCoverage{}
+	if sources == nil {
+		return functions, nil
+	}
+
+	var errs []error
+	for _, file := range coverage.GetSortedFiles() {
+		src, err := sources.ReadSource(file.FilePath)
+		if err != nil {
+			continue
+		}
+
+		fileFuncs, err := FileFunctions(file, src)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		functions = append(functions, fileFuncs...)
+	}
+
+	return functions, errors.Join(errs...)
+}
+
+// FileFunctions calcula a cobertura das funções declaradas em src, que deve
+// ser o código do arquivo descrito por file
+func FileFunctions(file *FileCoverage, src []byte) ([]FunctionCoverage, error) {
+	fset := token.NewFileSet()
+	parsed, err := parser.ParseFile(fset, file.FilePath, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao analisar %s: %w", file.FilePath, err)
+	}
+
+	blocks := append([]CoverageBlock(nil), file.Blocks...)
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].StartLine != blocks[j].StartLine {
+			return blocks[i].StartLine < blocks[j].StartLine
+		}
+		return blocks[i].StartCol < blocks[j].StartCol
+	})
+
+	functions := []FunctionCoverage{}
+	for _, decl := range parsed.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		start := fset.Position(fn.Pos())
+		end := fset.Position(fn.End())
+
+		fc := FunctionCoverage{
+			FilePath:  file.FilePath,
+			Name:      fn.Name.Name,
+			Receiver:  receiverType(fn),
+			StartLine: start.Line,
+			StartCol:  start.Column,
+			EndLine:   end.Line,
+			EndCol:    end.Column,
+		}
+		fc.attributeBlocks(blocks)
+		functions = append(functions, fc)
+	}
+
+	return functions, nil
+}
+
+// attributeBlocks soma os blocos contidos no intervalo da função, com a
+// mesma regra de go tool cover -func
+func (fc *FunctionCoverage) attributeBlocks(blocks []CoverageBlock) {
+	for _, b := range blocks {
+		if b.StartLine > fc.EndLine || (b.StartLine == fc.EndLine && b.StartCol >= fc.EndCol) {
+			break
+		}
+		if b.EndLine < fc.StartLine || (b.EndLine == fc.StartLine && b.EndCol <= fc.StartCol) {
+			continue
+		}
+
+		fc.TotalStmt += b.NumStmt
+		if b.Count > 0 {
+			fc.CoveredStmt += b.NumStmt
+		}
+	}
+
+	if fc.TotalStmt > 0 {
+		fc.Coverage = float64(fc.CoveredStmt) / float64(fc.TotalStmt) * 100
+	}
+}
+
+// receiverType formata o tipo do receptor de um método
+func receiverType(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	return types.ExprString(fn.Recv.List[0].Type)
+}
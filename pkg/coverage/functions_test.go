@@ -0,0 +1,135 @@
+package coverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const functionsSource = `package calc
+
+type Stack[T any] struct{ items []T }
+
+func (s *Stack[T]) Push(v T) {
+	s.items = append(s.items, v)
+}
+
+func Sum(a, b int) int {
+	if a < 0 {
+		return b
+	}
+	return a + b
+}
+
+func declared()
+`
+
+func TestFileFunctions(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/demo/calc.go:5.30,7.2 1 0
+example.com/demo/calc.go:9.24,10.11 1 1
+example.com/demo/calc.go:10.11,12.3 1 0
+example.com/demo/calc.go:13.2,13.14 1 1
+`)
+
+	functions, err := FileFunctions(cov.Files["example.com/demo/calc.go"], []byte(functionsSource))
+	if err != nil {
+		t.Fatalf("erro ao analisar funções: %v", err)
+	}
+
+	if len(functions) != 2 {
+		t.Fatalf("esperava 2 funções, obteve %d: %+v", len(functions), functions)
+	}
+
+	push := functions[0]
+	if push.FullName() != "(*Stack[T]).Push" || push.StartLine != 5 || push.EndLine != 7 {
+		t.Errorf("Push = %+v", push)
+	}
+	if push.TotalStmt != 1 || push.CoveredStmt != 0 || push.Coverage != 0 {
+		t.Errorf("Push statements = %d/%d", push.CoveredStmt, push.TotalStmt)
+	}
+
+	sum := functions[1]
+	if sum.FullName() != "Sum" || sum.Receiver != "" || sum.StartLine != 9 {
+		t.Errorf("Sum = %+v", sum)
+	}
+	if sum.TotalStmt != 3 || sum.CoveredStmt != 2 {
+		t.Errorf("Sum statements = %d/%d", sum.CoveredStmt, sum.TotalStmt)
+	}
+}
+
+func TestFileFunctionsInvalidSource(t *testing.T) {
+	file := &FileCoverage{FilePath: "bad.go"}
+	if _, err := FileFunctions(file, []byte("package x\nfunc {")); err == nil {
+		t.Error("esperava erro para código inválido")
+	}
+}
+
+func TestAnalyzeFunctions(t *testing.T) {
+	root := writeTestModule(t)
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cov := mustParse(t, `mode: set
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 1
+example.com/demo/pkg/calc/sum.go:4.11,6.3 1 0
+example.com/demo/pkg/calc/sum.go:7.2,7.14 1 1
+example.com/demo/pkg/calc/missing.go:1.1,2.2 1 1
+`)
+
+	functions, err := AnalyzeFunctions(cov, resolver)
+	if err != nil {
+		t.Fatalf("erro ao analisar funções: %v", err)
+	}
+	if len(functions) != 1 || functions[0].Name != "Sum" || functions[0].CoveredStmt != 2 || functions[0].TotalStmt != 3 {
+		t.Errorf("funções = %+v", functions)
+	}
+
+	var buf bytes.Buffer
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{Functions: functions}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	if !strings.Contains(html, `id="functionsTable"`) || !strings.Contains(html, "<td><code>Sum</code></td>") {
+		t.Error("HTML não contém a tabela de funções")
+	}
+}
+
+func TestAnalyzeFunctionsNilSources(t *testing.T) {
+	cov := mustParse(t, "mode: set\nexample.com/demo/pkg/calc/sum.go:3.24,4.11 1 1\n")
+	functions, err := AnalyzeFunctions(cov, nil)
+	if err != nil || len(functions) != 0 {
+		t.Errorf("funções = %+v, erro = %v; esperado vazio sem erro", functions, err)
+	}
+}
+
+func TestAnalyzeFunctionsSkipsInvalidSource(t *testing.T) {
+	root := writeTestModule(t)
+	bad := filepath.Join(root, "pkg", "calc", "bad.go")
+	if err := os.WriteFile(bad, []byte("package calc\nfunc {"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cov := mustParse(t, `mode: set
+example.com/demo/pkg/calc/bad.go:2.1,2.7 1 1
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 1
+example.com/demo/pkg/calc/sum.go:4.11,6.3 1 0
+example.com/demo/pkg/calc/sum.go:7.2,7.14 1 1
+`)
+
+	functions, err := AnalyzeFunctions(cov, resolver)
+	if err == nil || !strings.Contains(err.Error(), "bad.go") {
+		t.Errorf("erro = %v, esperado mencionar bad.go", err)
+	}
+	if len(functions) != 1 || functions[0].Name != "Sum" {
+		t.Errorf("funções = %+v, esperado apenas Sum", functions)
+	}
+}
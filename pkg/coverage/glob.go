@@ -0,0 +1,68 @@
+package coverage
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// globMatcher compara caminhos com um padrão glob. Além de * e ? (que não
+// atravessam "/"), o padrão aceita ** para qualquer número de diretórios.
+// Padrões sem "/" são comparados apenas com o nome base do caminho.
+type globMatcher struct {
+	pattern  string
+	re       *regexp.Regexp
+	baseOnly bool
+}
+
+func compileGlob(pattern string) (*globMatcher, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("padrão glob vazio")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	// Percorre runas, e não bytes, para não quebrar caracteres UTF-8
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == '*' && i+1 < len(runes) && runes[i+1] == '*':
+			i++
+			// "**/" também casa com zero diretórios
+			if i+1 < len(runes) && runes[i+1] == '/' {
+				i++
+				sb.WriteString("(?:.*/)?")
+			} else {
+				sb.WriteString(".*")
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, fmt.Errorf("padrão glob inválido %q: %w", pattern, err)
+	}
+
+	return &globMatcher{
+		pattern:  pattern,
+		re:       re,
+		baseOnly: !strings.Contains(pattern, "/"),
+	}, nil
+}
+
+// Match indica se o caminho (separado por "/") casa com o padrão
+func (gm *globMatcher) Match(name string) bool {
+	if gm.baseOnly {
+		name = path.Base(name)
+	}
+	return gm.re.MatchString(name)
+}
@@ -0,0 +1,121 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// generatePathReport gera o relatório HTML de um único arquivo com o caminho
+// informado
+func generatePathReport(t *testing.T, filePath string) string {
+	t.Helper()
+	cov := &ProjectCoverage{
+		Mode: "set",
+		Files: map[string]*FileCoverage{
+			filePath: {
+				FilePath:    filePath,
+				FileName:    filepath.Base(filePath),
+				Blocks:      []CoverageBlock{{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 2, NumStmt: 2, Count: 1}},
+				TotalStmt:   2,
+				CoveredStmt: 2,
+				Coverage:    100,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := NewHTMLGenerator(cov).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar HTML: %v", err)
+	}
+	return buf.String()
+}
+
+// reportDataJSON extrai o JSON do <script type="application/json">
+func reportDataJSON(t *testing.T, html string) scriptData {
+	t.Helper()
+	const open = `<script type="application/json" id="reportData">`
+	_, rest, ok := strings.Cut(html, open)
+	if !ok {
+		t.Fatal("HTML não contém o JSON do relatório")
+	}
+	blob, _, ok := strings.Cut(rest, "</script>")
+	if !ok {
+		t.Fatal("JSON do relatório não termina em </script>")
+	}
+
+	var data scriptData
+	if err := json.Unmarshal([]byte(blob), &data); err != nil {
+		t.Fatalf("JSON do relatório inválido: %v\n%s", err, blob)
+	}
+	return data
+}
+
+func TestHTMLGeneratorNoInlineHandlers(t *testing.T) {
+	html := generatePathReport(t, "pkg/a.go")
+	for _, attr := range []string{"onclick=", "onload=", "oninput=", "javascript:"} {
+		if strings.Contains(html, attr) {
+			t.Errorf("HTML contém %q", attr)
+		}
+	}
+
+	data := reportDataJSON(t, html)
+	file := data.Files["pkg/a.go"]
+	if file == nil || file.Name != "a.go" || file.Covered != 2 || file.Blocks[1] != 1 {
+		t.Errorf("dados do arquivo = %+v", file)
+	}
+	if data.Messages["sourceMissing"] == "" {
+		t.Error("JSON sem as mensagens do JavaScript")
+	}
+}
+
+// FuzzHTMLGeneratorPaths garante que caminhos hostis (aspas, <, \, quebras de
+// linha, </script>) não alterem a estrutura da página nem o JSON dos dados
+func FuzzHTMLGeneratorPaths(f *testing.F) {
+	for _, path := range []string{
+		"pkg/a.go",
+		"pkg/it's.go",
+		`pkg/"aspas".go`,
+		`pkg\windows\a.go`,
+		"pkg/quebra\nde linha.go",
+		"pkg/ separador .go",
+		"pkg/</script><script>alert(1)</script>.go",
+		`pkg/"><img src=x onerror=alert(1)>.go`,
+		"pkg/<!--<script>.go",
+		"pkg/');alert(1);('.go",
+		"{{.Title}}/a.go",
+	} {
+		f.Add(path)
+	}
+
+	f.Fuzz(func(t *testing.T, path string) {
+		// encoding/json troca bytes inválidos por U+FFFD
+		if !utf8.ValidString(path) {
+			return
+		}
+
+		html := generatePathReport(t, path)
+
+		// Um caminho inofensivo com os mesmos diretórios gera as mesmas tags
+		safe := strings.Map(func(r rune) rune {
+			if r == '/' {
+				return r
+			}
+			return 'x'
+		}, path)
+		if got, want := strings.Count(html, "<"), strings.Count(generatePathReport(t, safe), "<"); got != want {
+			t.Errorf("caminho %q gerou %d tags, esperado %d", path, got, want)
+		}
+		if got := strings.Count(html, "<script"); got != 2 {
+			t.Errorf("caminho %q gerou %d <script>, esperado 2", path, got)
+		}
+
+		file := reportDataJSON(t, html).Files[path]
+		if file == nil || file.Path != path || file.Name != filepath.Base(path) {
+			t.Errorf("JSON não preserva o caminho %q: %+v", path, file)
+		}
+	})
+}
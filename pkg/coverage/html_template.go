@@ -0,0 +1,277 @@
+package coverage
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"slices"
+	"sort"
+	"strings"
+)
+
+//go:embed templates/*.html templates/report.css
+var templateFS embed.FS
+
+// DefaultTemplates são os templates padrão do HTMLGenerator, um ponto de
+// partida para templates próprios (ver HTMLOptions.Templates)
+var DefaultTemplates fs.FS = mustSub(templateFS, "templates")
+
+//go:embed templates/report.css
+var reportCSS string
+
+// TemplateBlocks são os blocos do relatório HTML que podem ser redefinidos
+// com HTMLOptions.Templates ou HTMLOptions.Blocks. "report" é a página
+// inteira e chama os demais; "tree" recebe os nós []*HTMLTreeNode, "badge"
+// um HTMLCoverage, "delta" um *HTMLDelta e os outros recebem o HTMLReport.
+// Os blocos site* são as páginas do SiteGenerator: "site" é a página
+// inteira e, como os outros site*, recebe um *SitePage; a página inicial do
+// site também usa o bloco "stats". "sitescript" é o conteúdo de
+// assets/site.js e "tablesort", a ordenação das tabelas usada por ele e por
+// "scripts"; os dois não recebem dados.
+var TemplateBlocks = []string{
+	"report", "head", "header", "stats", "sidebar", "tree", "fileview", "functions", "scripts",
+	"badge", "delta", "tablesort",
+	"site", "siteheader", "siteindex", "sitepackage", "sitefile", "sitescript",
+}
+
+// HTMLReport é o modelo de dados passado aos templates do HTMLGenerator.
+//
+// Além dos campos, os templates têm as funções t e tr (tradução para o
+// idioma do relatório, como Language), percent, number, signed e decimal
+// (números no formato do idioma, ex.: {{percent .Total.Percent}}).
+type HTMLReport struct {
+	Title    string   // título do relatório (HTMLOptions.Title ou o padrão traduzido)
+	Language Language // idioma do relatório, usado no atributo lang
+	Mode     string   // modo do perfil: set, count ou atomic
+	Files    int      // quantidade de arquivos
+	Packages int      // quantidade de pacotes
+
+	Total     HTMLCoverage      // cobertura total do projeto
+	Tree      []*HTMLTreeNode   // árvore de pacotes (ver ProjectCoverage.BuildTree)
+	Functions []HTMLFunction    // cobertura por função; vazio sem HTMLOptions.Functions
+	Diff      *HTMLDiffSummary  // variação em relação à base; nil sem HTMLOptions.Diff
+	Patch     *HTMLPatchSummary // cobertura das linhas alteradas; nil sem HTMLOptions.Patch
+
+	Styles template.CSS // CSS padrão com as faixas de HTMLOptions.Buckets
+
+	// Data são os dados lidos pelo JavaScript do bloco scripts (mensagens e,
+	// por caminho, as linhas e o código de cada arquivo) em JSON, para um
+	// <script type="application/json">
+	Data template.JS
+}
+
+// HTMLCoverage é um valor de cobertura com a faixa a que ele pertence
+type HTMLCoverage struct {
+	Percent    float64 // percentual de 0-100
+	Covered    int     // statements cobertos
+	Statements int     // total de statements
+	Class      string  // classe CSS da faixa (coverage-<name>)
+	Label      string  // rótulo traduzido da faixa
+}
+
+// HTMLTreeNode é um nó da árvore de pacotes do relatório
+type HTMLTreeNode struct {
+	Name     string
+	Path     string // caminho completo; para arquivos, o FilePath
+	Dir      bool
+	Expanded bool // diretório aberto ao carregar a página
+	Active   bool // arquivo exibido ao carregar a página
+	Coverage HTMLCoverage
+	Delta    *HTMLDelta // variação do arquivo; nil sem HTMLOptions.Diff
+	Children []*HTMLTreeNode
+}
+
+// HTMLDelta é a variação de cobertura de um arquivo em relação à base
+type HTMLDelta struct {
+	Value float64 // variação em pontos percentuais
+	Class string  // delta-up, delta-down ou delta-none
+	New   bool    // arquivo inexistente na base
+}
+
+// HTMLFunction é uma linha da tabela de cobertura por função
+type HTMLFunction struct {
+	Name     string // nome qualificado pelo receptor (ver FunctionCoverage.FullName)
+	File     string
+	Line     int
+	Coverage HTMLCoverage
+}
+
+// HTMLDiffSummary é a variação total em relação à base
+type HTMLDiffSummary struct {
+	Delta     float64 // variação em pontos percentuais
+	Class     string  // delta-up, delta-down ou delta-none
+	BaseTotal float64 // cobertura total da base
+	Added     int     // arquivos novos
+	Removed   int     // arquivos removidos
+}
+
+// HTMLPatchSummary é a cobertura das linhas alteradas
+type HTMLPatchSummary struct {
+	Coverage HTMLCoverage
+	Files    int // arquivos com linhas alteradas
+}
+
+// templates retorna os templates padrão com as substituições de
+// HTMLOptions.Templates e HTMLOptions.Blocks
+func (hg *HTMLGenerator) templates() (*template.Template, error) {
+	lang := hg.opts.Language
+	tmpl := template.New("").Funcs(template.FuncMap{
+		"t":       lang.text,
+		"tr":      lang.tr,
+		"percent": lang.percent,
+		"number":  lang.number,
+		"signed":  lang.signed,
+		"decimal": lang.decimal,
+	})
+
+	if _, err := tmpl.ParseFS(DefaultTemplates, "*.html"); err != nil {
+		return nil, err
+	}
+	if hg.opts.Templates != nil {
+		if _, err := tmpl.ParseFS(hg.opts.Templates, "*.html"); err != nil {
+			return nil, fmt.Errorf("erro ao ler templates: %w", err)
+		}
+	}
+
+	names := make([]string, 0, len(hg.opts.Blocks))
+	for name := range hg.opts.Blocks {
+		if !slices.Contains(TemplateBlocks, name) {
+			return nil, fmt.Errorf("bloco de template desconhecido %q (use %s)", name, strings.Join(TemplateBlocks, ", "))
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, err := tmpl.New(name).Parse(hg.opts.Blocks[name]); err != nil {
+			return nil, fmt.Errorf("bloco %s: %w", name, err)
+		}
+	}
+	return tmpl, nil
+}
+
+// reportData monta o modelo de dados dos templates
+func (hg *HTMLGenerator) reportData() (*HTMLReport, error) {
+	jsonData, err := hg.scriptJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	data := hg.summary()
+	data.Styles = template.CSS(reportCSS + hg.opts.Buckets.css())
+	data.Data = jsonData
+
+	tree := hg.coverage.BuildTree()
+	data.Tree = hg.treeNodes(tree.Children, 0, firstFile(tree))
+
+	for _, fn := range hg.opts.Functions {
+		data.Functions = append(data.Functions, hg.htmlFunction(fn))
+	}
+	return data, nil
+}
+
+// summary retorna os totais do relatório, usados pelo bloco stats no HTML
+// único e na página inicial do site
+func (hg *HTMLGenerator) summary() *HTMLReport {
+	data := &HTMLReport{
+		Title:    hg.opts.title(),
+		Language: hg.opts.Language.resolve(),
+		Mode:     hg.coverage.Mode,
+		Files:    len(hg.coverage.Files),
+		Packages: len(hg.coverage.GetPackages()),
+	}
+
+	totalStmt, coveredStmt := 0, 0
+	for _, file := range hg.coverage.Files {
+		totalStmt += file.TotalStmt
+		coveredStmt += file.CoveredStmt
+	}
+	data.Total = hg.htmlCoverage(hg.coverage.GetTotalCoverage(), coveredStmt, totalStmt)
+
+	if diff := hg.opts.Diff; diff != nil {
+		data.Diff = &HTMLDiffSummary{
+			Delta:     diff.TotalDelta,
+			Class:     deltaClass(diff.TotalDelta),
+			BaseTotal: diff.BaseTotal,
+			Added:     len(diff.AddedFiles),
+			Removed:   len(diff.RemovedFiles),
+		}
+	}
+
+	if patch := hg.opts.Patch; patch != nil {
+		data.Patch = &HTMLPatchSummary{
+			Coverage: hg.htmlCoverage(patch.Coverage, patch.CoveredStmt, patch.TotalStmt),
+			Files:    len(patch.Files),
+		}
+	}
+	return data
+}
+
+// htmlFunction converte uma linha da tabela de funções
+func (hg *HTMLGenerator) htmlFunction(fn FunctionCoverage) HTMLFunction {
+	return HTMLFunction{
+		Name:     fn.FullName(),
+		File:     fn.FilePath,
+		Line:     fn.StartLine,
+		Coverage: hg.htmlCoverage(fn.Coverage, fn.CoveredStmt, fn.TotalStmt),
+	}
+}
+
+// treeNodes converte os nós da árvore de pacotes. Diretórios de primeiro
+// nível e os que contêm o arquivo ativo começam expandidos.
+func (hg *HTMLGenerator) treeNodes(nodes []*TreeNode, depth int, active *TreeNode) []*HTMLTreeNode {
+	result := make([]*HTMLTreeNode, 0, len(nodes))
+	for _, node := range nodes {
+		item := &HTMLTreeNode{
+			Name:     node.Name,
+			Path:     node.Path,
+			Dir:      node.IsDir(),
+			Coverage: hg.htmlCoverage(node.Coverage, node.CoveredStmt, node.TotalStmt),
+		}
+
+		if item.Dir {
+			item.Expanded = depth == 0 || (active != nil && strings.HasPrefix(active.Path, node.Path+"/"))
+			item.Children = hg.treeNodes(node.Children, depth+1, active)
+		} else {
+			item.Name = node.File.FileName
+			item.Active = node == active
+			item.Delta = hg.fileDelta(node.File.FilePath)
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// htmlCoverage retorna um valor de cobertura com a sua faixa
+func (hg *HTMLGenerator) htmlCoverage(percent float64, covered, total int) HTMLCoverage {
+	bucket := hg.opts.Buckets.Find(percent)
+	return HTMLCoverage{
+		Percent:    percent,
+		Covered:    covered,
+		Statements: total,
+		Class:      bucket.class(),
+		Label:      hg.opts.Language.text(bucket.label()),
+	}
+}
+
+// fileDelta retorna a variação de um arquivo em relação à base
+func (hg *HTMLGenerator) fileDelta(filePath string) *HTMLDelta {
+	if hg.opts.Diff == nil {
+		return nil
+	}
+
+	delta, ok := hg.opts.Diff.FileDelta(filePath)
+	if !ok {
+		return nil
+	}
+	return &HTMLDelta{Value: delta.Delta, Class: deltaClass(delta.Delta), New: delta.Status == DeltaAdded}
+}
+
+// mustSub retorna o subdiretório de um embed.FS
+func mustSub(fsys embed.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
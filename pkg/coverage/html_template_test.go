@@ -0,0 +1,87 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestDefaultTemplatesDefineBlocks(t *testing.T) {
+	tmpl, err := NewHTMLGenerator(nil).templates()
+	if err != nil {
+		t.Fatalf("erro ao ler templates padrão: %v", err)
+	}
+	for _, name := range TemplateBlocks {
+		if tmpl.Lookup(name) == nil {
+			t.Errorf("bloco %q não definido nos templates padrão", name)
+		}
+	}
+}
+
+func TestHTMLTemplateOverrides(t *testing.T) {
+	cov := mustParse(t, `mode: set
+pkg/a/one.go:1.1,2.2 3 1
+pkg/a/one.go:3.1,4.2 1 0
+pkg/b/two.go:1.1,2.2 1 1
+`)
+
+	templates := fstest.MapFS{
+		"tema.html": {Data: []byte(`{{define "header"}}<header class="tema">{{.Title}} · {{percent .Total.Percent}} · {{.Total.Label}}</header>{{end}}`)},
+	}
+	blocks := map[string]string{
+		"fileview": `<main id="content">{{len .Tree}} {{.Files}} {{.Mode}} {{.Language}}</main>`,
+		"tree":     `{{range .}}<li data-path="{{.Path}}">{{.Name}} {{.Coverage.Covered}}/{{.Coverage.Statements}}</li>{{template "tree" .Children}}{{end}}`,
+	}
+
+	var buf bytes.Buffer
+	opts := HTMLOptions{Title: "App <x>", Templates: templates, Blocks: blocks}
+	if err := NewHTMLGeneratorWithOptions(cov, opts).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar HTML: %v", err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{
+		`<header class="tema">App &lt;x&gt; · 80,0% · Excelente</header>`,
+		`<main id="content">1 2 set pt-BR</main>`,
+		`<li data-path="pkg/a/one.go">one.go 3/4</li>`,
+		// Blocos não redefinidos continuam os padrão
+		`<title>App &lt;x&gt;</title>`,
+		`<div class="stats-grid">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML não contém %q", want)
+		}
+	}
+	if strings.Contains(html, `<input type="text" id="searchInput"`) || strings.Contains(html, `<div class="empty-state">`) {
+		t.Error("HTML contém blocos padrão substituídos")
+	}
+}
+
+func TestHTMLTemplateErrors(t *testing.T) {
+	cov := mustParse(t, "mode: set\npkg/a.go:1.1,2.2 1 1\n")
+
+	tests := []struct {
+		name string
+		opts HTMLOptions
+		want string
+	}{
+		{"bloco desconhecido", HTMLOptions{Blocks: map[string]string{"rodape": "x"}}, `"rodape"`},
+		{"sintaxe inválida", HTMLOptions{Blocks: map[string]string{"header": "{{.Title"}}, "header"},
+		{"campo inexistente", HTMLOptions{Blocks: map[string]string{"header": "{{.Autor}}"}}, "Autor"},
+		{"diretório sem templates", HTMLOptions{Templates: fstest.MapFS{"leia.txt": {}}}, "templates"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewHTMLGeneratorWithOptions(cov, tt.opts).Generate(&buf)
+			if err == nil {
+				t.Fatal("esperava erro")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("erro = %v, esperado mencionar %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package coverage
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Language é o idioma das mensagens dos relatórios e dos erros de parse,
+// no formato BCP 47 usado no atributo lang do HTML
+type Language string
+
+// Idiomas com catálogo de mensagens. O português é o idioma de origem: as
+// mensagens do código são as chaves dos catálogos dos demais idiomas.
+const (
+	LanguagePortuguese Language = "pt-BR"
+	LanguageEnglish    Language = "en"
+	LanguageSpanish    Language = "es"
+)
+
+// DefaultLanguage é o idioma usado quando nenhum é informado
+const DefaultLanguage = LanguagePortuguese
+
+// Languages são os idiomas suportados
+var Languages = []Language{LanguagePortuguese, LanguageEnglish, LanguageSpanish}
+
+// numberFormat são os separadores de números de um idioma
+type numberFormat struct {
+	decimal   string
+	thousands string
+}
+
+var numberFormats = map[Language]numberFormat{
+	LanguagePortuguese: {decimal: ",", thousands: "."},
+	LanguageEnglish:    {decimal: ".", thousands: ","},
+	LanguageSpanish:    {decimal: ",", thousands: "."},
+}
+
+// ParseLanguage interpreta um código de idioma como "pt-BR", "en-US" ou
+// "es". Apenas o idioma principal é considerado; o vazio retorna
+// DefaultLanguage.
+func ParseLanguage(code string) (Language, error) {
+	if code == "" {
+		return DefaultLanguage, nil
+	}
+	primary, _, _ := strings.Cut(strings.ToLower(strings.ReplaceAll(code, "_", "-")), "-")
+	for _, lang := range Languages {
+		if langPrimary, _, _ := strings.Cut(strings.ToLower(string(lang)), "-"); langPrimary == primary {
+			return lang, nil
+		}
+	}
+	return "", fmt.Errorf("idioma não suportado: %q (use pt-BR, en ou es)", code)
+}
+
+// Validate verifica se o idioma tem catálogo; o vazio é aceito como
+// DefaultLanguage
+func (lang Language) Validate() error {
+	if _, ok := numberFormats[lang.resolve()]; !ok {
+		return fmt.Errorf("idioma não suportado: %q (use pt-BR, en ou es)", string(lang))
+	}
+	return nil
+}
+
+// resolve retorna o idioma ou DefaultLanguage se vazio
+func (lang Language) resolve() Language {
+	if lang == "" {
+		return DefaultLanguage
+	}
+	return lang
+}
+
+// text traduz uma mensagem do catálogo, retornando a própria mensagem
+// quando não há tradução
+func (lang Language) text(message string) string {
+	if translated, ok := catalogs[lang.resolve()][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// tr traduz o formato e aplica os argumentos, como fmt.Sprintf. Números
+// exibidos ao usuário devem ser formatados antes com percent ou number.
+func (lang Language) tr(format string, args ...any) string {
+	return fmt.Sprintf(lang.text(format), args...)
+}
+
+// plural traduz e formata com n a mensagem no singular, se n for 1, ou no
+// plural
+func (lang Language) plural(n int, singular, plural string) string {
+	if n == 1 {
+		return lang.tr(singular, n)
+	}
+	return lang.tr(plural, n)
+}
+
+// Text traduz uma mensagem do catálogo. Text, Sprintf e Errorf permitem que
+// programas como o CLI exibam as próprias mensagens no idioma dos
+// relatórios; mensagens sem tradução são retornadas em português.
+func (lang Language) Text(message string) string {
+	return lang.text(message)
+}
+
+// Sprintf traduz o formato e aplica os argumentos, como fmt.Sprintf
+func (lang Language) Sprintf(format string, args ...any) string {
+	return lang.tr(format, args...)
+}
+
+// Errorf traduz o formato e cria um erro, como fmt.Errorf (inclusive %w)
+func (lang Language) Errorf(format string, args ...any) error {
+	return fmt.Errorf(lang.text(format), args...)
+}
+
+// decimal formata um número com as casas decimais e os separadores do
+// idioma
+func (lang Language) decimal(value float64, decimals int) string {
+	format := numberFormats[lang.resolve()]
+	text := strconv.FormatFloat(math.Abs(value), 'f', decimals, 64)
+	integer, fraction, _ := strings.Cut(text, ".")
+
+	var sb strings.Builder
+	if value < 0 && strings.Trim(text, "0.") != "" {
+		sb.WriteByte('-')
+	}
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteString(format.thousands)
+		}
+		sb.WriteRune(digit)
+	}
+	if fraction != "" {
+		sb.WriteString(format.decimal)
+		sb.WriteString(fraction)
+	}
+	return sb.String()
+}
+
+// percent formata uma porcentagem com uma casa decimal (ex.: "85,3%")
+func (lang Language) percent(value float64) string {
+	return lang.decimal(value, 1) + "%"
+}
+
+// number formata um inteiro com o separador de milhares do idioma
+func (lang Language) number(n int) string {
+	return lang.decimal(float64(n), 0)
+}
+
+// signed formata uma variação com sinal e uma casa decimal (ex.: "+2,5")
+func (lang Language) signed(value float64) string {
+	text := lang.decimal(value, 1)
+	if !strings.HasPrefix(text, "-") {
+		text = "+" + text
+	}
+	return text
+}
+
+// localizedError é um erro cuja mensagem vem do catálogo e só é traduzida
+// na formatação, quando quem o reporta já conhece o idioma (ver
+// ParseError). Error usa DefaultLanguage.
+type localizedError struct {
+	cause  error // causa para errors.Is; opcional
+	format string
+	args   []any
+}
+
+func newLocalizedError(cause error, format string, args ...any) *localizedError {
+	return &localizedError{cause: cause, format: format, args: args}
+}
+
+func (e *localizedError) Error() string {
+	return e.in(DefaultLanguage)
+}
+
+func (e *localizedError) Unwrap() error {
+	return e.cause
+}
+
+// in formata a mensagem no idioma informado
+func (e *localizedError) in(lang Language) string {
+	message := lang.tr(e.format, e.args...)
+	if e.cause == nil {
+		return message
+	}
+	return lang.text(e.cause.Error()) + ": " + message
+}
+
+// localizeError formata err no idioma, traduzindo-o se for um
+// localizedError
+func localizeError(err error, lang Language) string {
+	if le, ok := err.(*localizedError); ok {
+		return le.in(lang)
+	}
+	return err.Error()
+}
@@ -0,0 +1,348 @@
+package coverage
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    Language
+		wantErr bool
+	}{
+		{"", DefaultLanguage, false},
+		{"pt-BR", LanguagePortuguese, false},
+		{"pt_br", LanguagePortuguese, false},
+		{"pt", LanguagePortuguese, false},
+		{"en", LanguageEnglish, false},
+		{"en-US", LanguageEnglish, false},
+		{"ES", LanguageSpanish, false},
+		{"es-419", LanguageSpanish, false},
+		{"e", "", true},
+		{"fr", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLanguage(tt.code)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLanguage(%q) erro = %v, esperava erro: %v", tt.code, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseLanguage(%q) = %q, esperado %q", tt.code, got, tt.want)
+		}
+	}
+
+	if err := Language("fr").Validate(); err == nil {
+		t.Error("esperava erro para idioma sem catálogo")
+	}
+	if err := Language("").Validate(); err != nil {
+		t.Errorf("idioma vazio deveria ser aceito: %v", err)
+	}
+}
+
+func TestLanguageNumbers(t *testing.T) {
+	tests := []struct {
+		lang Language
+		got  string
+		want string
+	}{
+		{LanguagePortuguese, LanguagePortuguese.percent(85.26), "85,3%"},
+		{LanguageEnglish, LanguageEnglish.percent(85.26), "85.3%"},
+		{LanguageSpanish, LanguageSpanish.percent(100), "100,0%"},
+		{LanguagePortuguese, LanguagePortuguese.number(1234567), "1.234.567"},
+		{LanguageEnglish, LanguageEnglish.number(1234567), "1,234,567"},
+		{LanguageEnglish, LanguageEnglish.number(999), "999"},
+		{LanguagePortuguese, LanguagePortuguese.signed(2.5), "+2,5"},
+		{LanguageEnglish, LanguageEnglish.signed(-2.5), "-2.5"},
+		{LanguageEnglish, LanguageEnglish.signed(-0.01), "+0.0"},
+		{LanguageEnglish, LanguageEnglish.decimal(-1234.5, 1), "-1,234.5"},
+		{"", Language("").percent(50), "50,0%"},
+	}
+
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: obtido %q, esperado %q", tt.lang, tt.got, tt.want)
+		}
+	}
+}
+
+// TestCatalogsComplete verifica que toda mensagem traduzível do pacote tem
+// tradução em todos os catálogos
+func TestCatalogsComplete(t *testing.T) {
+	messages := map[string]bool{DefaultReportTitle: true}
+	for _, bucket := range DefaultCoverageBuckets {
+		messages[bucket.Label] = true
+	}
+	for _, err := range []error{ErrInvalidMode, ErrInvalidFormat, ErrInvalidNumber, ErrInvalidRange} {
+		messages[err.Error()] = true
+	}
+
+	// Literais passados às funções que consultam o catálogo
+	translators := map[string]int{"text": 0, "tr": 0, "newParseError": 2, "newLocalizedError": 1, "parseField": 1,
+		"Text": 0, "Sprintf": 0, "Errorf": 0, "lineError": 1}
+	// O CLI traduz também os erros de uso e as descrições das flags
+	cliTranslators := map[string]int{"usageError": 0, "Var": 2, "StringVar": 3, "BoolVar": 3, "IntVar": 3, "Float64Var": 3}
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliFiles, err := filepath.Glob("../../cmd/coverage-report/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files = append(files, cliFiles...)
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			var fn string
+			switch f := call.Fun.(type) {
+			case *ast.Ident:
+				fn = f.Name
+			case *ast.SelectorExpr:
+				if pkg, ok := f.X.(*ast.Ident); ok && pkg.Name == "fmt" {
+					return true
+				}
+				fn = f.Sel.Name
+			}
+			arg, ok := translators[fn]
+			if !ok && strings.HasPrefix(name, "../") {
+				arg, ok = cliTranslators[fn]
+			}
+			args := []int{arg}
+			if fn == "plural" {
+				args, ok = []int{1, 2}, true
+			}
+			for _, arg := range args {
+				if !ok || len(call.Args) <= arg {
+					return true
+				}
+				if lit, ok := call.Args[arg].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					message, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						t.Fatal(err)
+					}
+					messages[message] = true
+				}
+			}
+			return true
+		})
+	}
+
+	// Literais de {{t "..."}} e {{tr "..." ...}} nos templates padrão
+	templateText := regexp.MustCompile(`\{\{-?\s*\(?tr? ("(?:[^"\\]|\\.)*")`)
+	err = fs.WalkDir(DefaultTemplates, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(DefaultTemplates, name)
+		if err != nil {
+			return err
+		}
+		for _, match := range templateText.FindAllStringSubmatch(string(data), -1) {
+			message, err := strconv.Unquote(match[1])
+			if err != nil {
+				return err
+			}
+			messages[message] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) < 50 {
+		t.Fatalf("apenas %d mensagens encontradas", len(messages))
+	}
+
+	for _, lang := range []Language{LanguageEnglish, LanguageSpanish} {
+		for message := range messages {
+			if _, ok := catalogs[lang][message]; !ok {
+				t.Errorf("%s: sem tradução para %q", lang, message)
+			}
+		}
+		for message := range catalogs[lang] {
+			if !messages[message] {
+				t.Errorf("%s: tradução não usada %q", lang, message)
+			}
+		}
+	}
+}
+
+func TestLocalizedParseErrors(t *testing.T) {
+	profile := "mode: set\npkg/a.go:1.1,2.x 1 1\n"
+
+	tests := []struct {
+		lang Language
+		want string
+	}{
+		{"", `linha 2, coluna 16: número inválido: coluna "x"`},
+		{LanguageEnglish, `line 2, column 16: invalid number: column "x"`},
+		{LanguageSpanish, `línea 2, columna 16: número no válido: columna "x"`},
+	}
+
+	for _, tt := range tests {
+		_, _, err := ParseCoverageFileWithOptions(strings.NewReader(profile), ParseOptions{Validation: ValidateStrict, Language: tt.lang})
+		if err == nil {
+			t.Fatalf("%s: esperava erro", tt.lang)
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: erro = %q, esperado conter %q", tt.lang, err, tt.want)
+		}
+		if !errors.Is(err, ErrInvalidNumber) {
+			t.Errorf("%s: erro não é ErrInvalidNumber", tt.lang)
+		}
+	}
+
+	_, _, err := ParseCoverageFileWithOptions(strings.NewReader(""), ParseOptions{Language: LanguageEnglish})
+	if err == nil || err.Error() != "empty coverage file" {
+		t.Errorf("erro = %v, esperado empty coverage file", err)
+	}
+}
+
+func TestLocalizedFormatErrors(t *testing.T) {
+	english := ParseOptions{Language: LanguageEnglish}
+	spanish := ParseOptions{Language: LanguageSpanish}
+
+	tests := []struct {
+		name  string
+		parse func() error
+		want  string
+		cause error
+	}{
+		{"LCOV", func() error {
+			_, err := ParseLCOVWithOptions(strings.NewReader("SF:a.go\nDA:x,1\n"), english)
+			return err
+		}, `line 2: invalid number: line number "x"`, ErrInvalidNumber},
+		{"LCOV padrão", func() error {
+			_, err := ParseLCOV(strings.NewReader("DA:1,1\n"))
+			return err
+		}, "linha 1: formato inválido: DA fora de um registro SF", ErrInvalidFormat},
+		{"diff", func() error {
+			_, err := ParseUnifiedDiffWithOptions(strings.NewReader("@@ -1 +1 @@\n"), spanish)
+			return err
+		}, "línea 1: formato no válido: hunk sin encabezado de archivo", ErrInvalidFormat},
+		{"JSON", func() error {
+			_, err := ParseJSONReportWithOptions(strings.NewReader(`{"schemaVersion": 2}`), english)
+			return err
+		}, "unsupported schema version: 2 (supported: 1)", nil},
+	}
+
+	for _, tt := range tests {
+		err := tt.parse()
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s: erro = %v, esperado %q", tt.name, err, tt.want)
+		}
+		if tt.cause != nil && !errors.Is(err, tt.cause) {
+			t.Errorf("%s: erro não é %v", tt.name, tt.cause)
+		}
+	}
+}
+
+func TestLocalizedReports(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/p/a.go:1.1,2.1 1500 1
+example.com/p/a.go:3.1,4.1 500 0
+`)
+
+	var buf bytes.Buffer
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{Language: LanguageEnglish}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar HTML: %v", err)
+	}
+	for _, want := range []string{
+		`<html lang="en">`,
+		"<title>Test Coverage Report</title>",
+		`title="Good">Total Coverage: 75.0%</div>`,
+		`placeholder="Search file..."`,
+		`<div class="stat-value">1,500</div>`,
+		`<div class="stat-subtext">of 2,000</div>`,
+		`"sourceMissing":"Source code not available for this file"`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("HTML não contém %q", want)
+		}
+	}
+	if strings.Contains(buf.String(), "Cobertura") {
+		t.Error("HTML em inglês contém textos em português")
+	}
+
+	buf.Reset()
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar HTML: %v", err)
+	}
+	for _, want := range []string{`<html lang="pt-BR">`, "Cobertura Total: 75,0%", `<div class="stat-value">1.500</div>`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("HTML padrão não contém %q", want)
+		}
+	}
+
+	buf.Reset()
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{Language: LanguageSpanish}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar Markdown: %v", err)
+	}
+	for _, want := range []string{
+		"## 🟡 Cobertura: 75,0%",
+		"**1.500/2.000** sentencias cubiertas en 1 archivos de 1 paquetes",
+		"### Archivos con menor cobertura",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Markdown não contém %q:\n%s", want, buf.String())
+		}
+	}
+
+	dir := t.TempDir()
+	if err := NewSiteGenerator(cov, HTMLOptions{Language: LanguageEnglish}).Generate(dir); err != nil {
+		t.Fatalf("erro ao gerar site: %v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`<html lang="en">`, "Coverage: 75.0%"} {
+		if !strings.Contains(string(index), want) {
+			t.Errorf("site não contém %q", want)
+		}
+	}
+
+	base := mustParse(t, "mode: set\nexample.com/p/a.go:1.1,2.1 1500 1\nexample.com/p/a.go:3.1,4.1 500 1\n")
+	buf.Reset()
+	if err := CompareCoverage(base, cov).WriteLocalizedMarkdown(&buf, LanguageEnglish); err != nil {
+		t.Fatalf("erro ao gerar o resumo da comparação: %v", err)
+	}
+	if !strings.Contains(buf.String(), "## 📊 Coverage: 75.0% (-25.0 pp)") {
+		t.Errorf("resumo da comparação não traduzido:\n%s", buf.String())
+	}
+	if err := CompareCoverage(base, cov).WriteLocalizedMarkdown(&buf, "fr"); err == nil {
+		t.Error("esperava erro do resumo da comparação para idioma inválido")
+	}
+
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{Language: "fr"}).Generate(&buf); err == nil {
+		t.Error("esperava erro do HTML para idioma inválido")
+	}
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{Language: "fr"}).Generate(&buf); err == nil {
+		t.Error("esperava erro do Markdown para idioma inválido")
+	}
+}
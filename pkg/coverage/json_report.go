@@ -0,0 +1,231 @@
+package coverage
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// JSONSchemaVersion é a versão do formato JSON gerado. Ela só muda quando o
+// formato deixa de ser compatível; campos novos e opcionais não alteram a
+// versão.
+const JSONSchemaVersion = 1
+
+//go:embed schema/coverage-report-v1.schema.json
+var jsonSchema []byte
+
+// JSONSchema retorna o JSON Schema (draft 2020-12) do formato gerado por
+// JSONGenerator
+func JSONSchema() []byte {
+	return append([]byte(nil), jsonSchema...)
+}
+
+// JSONReport é o documento JSON do relatório. Os tipos JSON* formam o
+// contrato público do formato e são independentes das estruturas internas.
+type JSONReport struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	Mode          string         `json:"mode"`
+	Totals        JSONTotals     `json:"totals"`
+	Packages      []JSONPackage  `json:"packages"`
+	Files         []JSONFile     `json:"files"`
+	Functions     []JSONFunction `json:"functions,omitempty"`
+}
+
+// JSONTotals agrega a cobertura do projeto
+type JSONTotals struct {
+	Files             int     `json:"files"`
+	Statements        int     `json:"statements"`
+	CoveredStatements int     `json:"coveredStatements"`
+	Coverage          float64 `json:"coverage"`
+}
+
+// JSONPackage é a cobertura de um pacote
+type JSONPackage struct {
+	ImportPath        string  `json:"importPath"`
+	Files             int     `json:"files"`
+	Statements        int     `json:"statements"`
+	CoveredStatements int     `json:"coveredStatements"`
+	Coverage          float64 `json:"coverage"`
+}
+
+// JSONFile é a cobertura de um arquivo com os blocos do perfil
+type JSONFile struct {
+	Path              string      `json:"path"`
+	Name              string      `json:"name"`
+	Package           string      `json:"package"`
+	Statements        int         `json:"statements"`
+	CoveredStatements int         `json:"coveredStatements"`
+	Coverage          float64     `json:"coverage"`
+	Blocks            []JSONBlock `json:"blocks"`
+}
+
+// JSONBlock é um bloco de cobertura
+type JSONBlock struct {
+	StartLine  int `json:"startLine"`
+	StartCol   int `json:"startCol"`
+	EndLine    int `json:"endLine"`
+	EndCol     int `json:"endCol"`
+	Statements int `json:"statements"`
+	Count      int `json:"count"` // contadores negativos, aceitos sem validação, viram 0
+}
+
+// JSONFunction é a cobertura de uma função (ver AnalyzeFunctions)
+type JSONFunction struct {
+	File              string  `json:"file"`
+	Name              string  `json:"name"`
+	Receiver          string  `json:"receiver,omitempty"`
+	StartLine         int     `json:"startLine"`
+	EndLine           int     `json:"endLine"`
+	Statements        int     `json:"statements"`
+	CoveredStatements int     `json:"coveredStatements"`
+	Coverage          float64 `json:"coverage"`
+}
+
+// JSONOptions configura o JSONGenerator
+type JSONOptions struct {
+	// Functions adiciona a cobertura por função ao documento
+	Functions []FunctionCoverage
+	// Indent formata o JSON com indentação
+	Indent bool
+}
+
+// JSONGenerator converte a cobertura para o formato JSON versionado
+type JSONGenerator struct {
+	coverage *ProjectCoverage
+	opts     JSONOptions
+}
+
+// NewJSONGenerator cria um gerador de JSON
+func NewJSONGenerator(coverage *ProjectCoverage, opts JSONOptions) *JSONGenerator {
+	return &JSONGenerator{coverage: coverage, opts: opts}
+}
+
+// Report monta o documento JSON sem serializá-lo
+func (jg *JSONGenerator) Report() *JSONReport {
+	report := &JSONReport{
+		SchemaVersion: JSONSchemaVersion,
+		Mode:          jg.coverage.Mode,
+		Packages:      []JSONPackage{},
+		Files:         []JSONFile{},
+	}
+
+	for _, pkg := range jg.coverage.GetPackages() {
+		report.Packages = append(report.Packages, JSONPackage{
+			ImportPath:        pkg.ImportPath,
+			Files:             len(pkg.Files),
+			Statements:        pkg.TotalStmt,
+			CoveredStatements: pkg.CoveredStmt,
+			Coverage:          pkg.Coverage,
+		})
+		report.Totals.Statements += pkg.TotalStmt
+		report.Totals.CoveredStatements += pkg.CoveredStmt
+	}
+
+	for _, file := range jg.coverage.GetSortedFiles() {
+		jsonFile := JSONFile{
+			Path:              file.FilePath,
+			Name:              file.FileName,
+			Package:           packagePath(file.FilePath),
+			Statements:        file.TotalStmt,
+			CoveredStatements: file.CoveredStmt,
+			Coverage:          file.Coverage,
+			Blocks:            make([]JSONBlock, 0, len(file.Blocks)),
+		}
+		for _, b := range file.Blocks {
+			jsonFile.Blocks = append(jsonFile.Blocks, JSONBlock{
+				StartLine:  b.StartLine,
+				StartCol:   b.StartCol,
+				EndLine:    b.EndLine,
+				EndCol:     b.EndCol,
+				Statements: b.NumStmt,
+				Count:      max(b.Count, 0),
+			})
+		}
+		report.Files = append(report.Files, jsonFile)
+	}
+
+	for _, fn := range jg.opts.Functions {
+		report.Functions = append(report.Functions, JSONFunction{
+			File:              fn.FilePath,
+			Name:              fn.Name,
+			Receiver:          fn.Receiver,
+			StartLine:         fn.StartLine,
+			EndLine:           fn.EndLine,
+			Statements:        fn.TotalStmt,
+			CoveredStatements: fn.CoveredStmt,
+			Coverage:          fn.Coverage,
+		})
+	}
+
+	report.Totals.Files = len(report.Files)
+	report.Totals.Coverage = jg.coverage.GetTotalCoverage()
+	return report
+}
+
+// Generate escreve o documento JSON no writer
+func (jg *JSONGenerator) Generate(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	if jg.opts.Indent {
+		encoder.SetIndent("", "  ")
+	}
+	if err := encoder.Encode(jg.Report()); err != nil {
+		return fmt.Errorf("erro ao gerar JSON: %w", err)
+	}
+	return nil
+}
+
+// ParseJSONReport lê um documento gerado por JSONGenerator e reconstrói a
+// cobertura a partir dos blocos. Os totais do documento são recalculados.
+func ParseJSONReport(reader io.Reader) (*ProjectCoverage, error) {
+	return ParseJSONReportWithOptions(reader, ParseOptions{})
+}
+
+// ParseJSONReportWithOptions é ParseJSONReport com os erros no idioma de
+// opts.Language
+func ParseJSONReportWithOptions(reader io.Reader, opts ParseOptions) (*ProjectCoverage, error) {
+	lang := opts.Language
+	if err := lang.Validate(); err != nil {
+		return nil, err
+	}
+	var report JSONReport
+	if err := json.NewDecoder(reader).Decode(&report); err != nil {
+		return nil, fmt.Errorf(lang.text("erro ao ler relatório JSON: %w"), err)
+	}
+
+	if report.SchemaVersion != JSONSchemaVersion {
+		return nil, errors.New(lang.tr("versão de schema não suportada: %d (suportada: %d)", report.SchemaVersion, JSONSchemaVersion))
+	}
+
+	builder := newProfileBuilder(report.Mode)
+	for _, file := range report.Files {
+		if file.Path == "" {
+			return nil, errors.New(lang.text("arquivo sem caminho no relatório JSON"))
+		}
+		for _, b := range file.Blocks {
+			block := CoverageBlock{
+				StartLine: b.StartLine,
+				StartCol:  b.StartCol,
+				EndLine:   b.EndLine,
+				EndCol:    b.EndCol,
+				NumStmt:   b.Statements,
+				Count:     b.Count,
+			}
+			if err := builder.add(file.Path, block); err != nil {
+				return nil, errors.New(localizeError(err, lang))
+			}
+		}
+		// Arquivos sem blocos também fazem parte do relatório
+		if _, ok := builder.coverage.Files[file.Path]; !ok {
+			builder.coverage.Files[file.Path] = &FileCoverage{
+				FilePath: file.Path,
+				FileName: filepath.Base(file.Path),
+				Blocks:   []CoverageBlock{},
+			}
+		}
+	}
+
+	return builder.finish(), nil
+}
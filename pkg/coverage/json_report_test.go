@@ -0,0 +1,218 @@
+package coverage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	cov := mustParse(t, `mode: count
+github.com/user/project/main.go:5.13,7.2 2 3
+github.com/user/project/main.go:9.13,11.2 1 0
+github.com/user/project/pkg/util.go:3.20,5.2 1 1
+`)
+	cov.Files["github.com/user/project/empty.go"] = &FileCoverage{
+		FilePath: "github.com/user/project/empty.go",
+		FileName: "empty.go",
+		Blocks:   []CoverageBlock{},
+	}
+
+	var buf bytes.Buffer
+	if err := NewJSONGenerator(cov, JSONOptions{Indent: true}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar JSON: %v", err)
+	}
+
+	parsed, err := ParseJSONReport(&buf)
+	if err != nil {
+		t.Fatalf("erro ao ler JSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(parsed, cov) {
+		t.Errorf("round-trip alterou a cobertura:\nesperado %+v\nobtido   %+v", cov, parsed)
+	}
+}
+
+func TestJSONReportContent(t *testing.T) {
+	cov := mustParse(t, `mode: set
+github.com/user/project/main.go:5.13,7.2 2 1
+github.com/user/project/main.go:9.13,11.2 1 0
+github.com/user/project/pkg/util.go:3.20,5.2 1 1
+`)
+	functions := []FunctionCoverage{{
+		FilePath: "github.com/user/project/main.go", Name: "main",
+		StartLine: 5, EndLine: 7, TotalStmt: 2, CoveredStmt: 2, Coverage: 100,
+	}}
+
+	report := NewJSONGenerator(cov, JSONOptions{Functions: functions}).Report()
+
+	if report.SchemaVersion != JSONSchemaVersion || report.Mode != "set" {
+		t.Errorf("cabeçalho inesperado: versão %d, modo %q", report.SchemaVersion, report.Mode)
+	}
+	wantTotals := JSONTotals{Files: 2, Statements: 4, CoveredStatements: 3, Coverage: 75}
+	if report.Totals != wantTotals {
+		t.Errorf("totais: esperado %+v, obtido %+v", wantTotals, report.Totals)
+	}
+	if len(report.Packages) != 2 || report.Packages[0].ImportPath != "github.com/user/project" || report.Packages[0].CoveredStatements != 2 {
+		t.Errorf("pacotes inesperados: %+v", report.Packages)
+	}
+	if len(report.Files) != 2 || report.Files[1].Package != "github.com/user/project/pkg" || len(report.Files[0].Blocks) != 2 {
+		t.Errorf("arquivos inesperados: %+v", report.Files)
+	}
+	if len(report.Functions) != 1 || report.Functions[0].Name != "main" {
+		t.Errorf("funções inesperadas: %+v", report.Functions)
+	}
+}
+
+func TestJSONMatchesSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("schema inválido: %v", err)
+	}
+
+	// O contador negativo é aceito sem validação e não pode violar o schema
+	cov := mustParse(t, `mode: atomic
+github.com/user/project/main.go:5.13,7.2 2 3
+github.com/user/project/pkg/util.go:3.20,5.2 1 0
+github.com/user/project/pkg/util.go:6.1,7.2 1 -2
+`)
+	functions := []FunctionCoverage{{
+		FilePath: "github.com/user/project/main.go", Name: "Run", Receiver: "*Server",
+		StartLine: 5, EndLine: 7, TotalStmt: 2, CoveredStmt: 2, Coverage: 100,
+	}}
+
+	var buf bytes.Buffer
+	if err := NewJSONGenerator(cov, JSONOptions{Functions: functions}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if err := validateSchema(schema, schema, doc, "$"); err != nil {
+		t.Errorf("documento não segue o schema: %v", err)
+	}
+
+	// Campos novos e modos desconhecidos não quebram validadores da v1
+	var extended map[string]any
+	json.Unmarshal(buf.Bytes(), &extended)
+	extended["extra"] = true
+	extended["mode"] = "custom"
+	extended["totals"].(map[string]any)["branches"] = 3
+	if err := validateSchema(schema, schema, extended, "$"); err != nil {
+		t.Errorf("documento com campos novos rejeitado: %v", err)
+	}
+
+	// O validador precisa rejeitar documentos fora do schema
+	var invalid map[string]any
+	json.Unmarshal(buf.Bytes(), &invalid)
+	invalid["totals"].(map[string]any)["files"] = "dois"
+	if err := validateSchema(schema, schema, invalid, "$"); err == nil {
+		t.Error("esperado erro para tipo inválido")
+	}
+}
+
+func TestParseJSONReportErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"JSON inválido", `{`, "erro ao ler relatório JSON"},
+		{"versão futura", `{"schemaVersion": 2, "mode": "set", "files": []}`, "versão de schema não suportada"},
+		{"sem versão", `{"mode": "set", "files": []}`, "versão de schema não suportada"},
+		{"arquivo sem caminho", `{"schemaVersion": 1, "mode": "set", "files": [{"blocks": []}]}`, "arquivo sem caminho"},
+		{"bloco divergente", `{"schemaVersion": 1, "mode": "set", "files": [{"path": "a.go", "blocks": [
+			{"startLine": 1, "startCol": 1, "endLine": 2, "endCol": 1, "statements": 1, "count": 1},
+			{"startLine": 1, "startCol": 1, "endLine": 2, "endCol": 1, "statements": 2, "count": 1}]}]}`, "a.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJSONReport(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("esperado erro contendo %q, obtido %v", tt.want, err)
+			}
+		})
+	}
+}
+
+// validateSchema valida value contra o subconjunto de JSON Schema usado em
+// schema/coverage-report-v1.schema.json
+func validateSchema(root, schema map[string]any, value any, at string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/$defs/")
+		def, ok := root["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: referência desconhecida %s", at, ref)
+		}
+		return validateSchema(root, def, value, at)
+	}
+
+	if c, ok := schema["const"]; ok && !reflect.DeepEqual(c, value) {
+		return fmt.Errorf("%s: esperado %v, obtido %v", at, c, value)
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || reflect.DeepEqual(e, value)
+		}
+		if !found {
+			return fmt.Errorf("%s: valor %v fora de %v", at, value, enum)
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: esperado objeto", at)
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for _, req := range schema["required"].([]any) {
+			if _, ok := obj[req.(string)]; !ok {
+				return fmt.Errorf("%s: campo obrigatório %s ausente", at, req)
+			}
+		}
+		for key, v := range obj {
+			prop, ok := props[key].(map[string]any)
+			if !ok {
+				continue
+			}
+			if err := validateSchema(root, prop, v, at+"."+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: esperado array", at)
+		}
+		items, _ := schema["items"].(map[string]any)
+		for i, v := range arr {
+			if err := validateSchema(root, items, v, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: esperado string", at)
+		}
+	case "integer", "number":
+		n, ok := value.(float64)
+		if !ok || (schema["type"] == "integer" && n != float64(int64(n))) {
+			return fmt.Errorf("%s: esperado %s, obtido %v", at, schema["type"], value)
+		}
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			return fmt.Errorf("%s: %v menor que %v", at, n, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			return fmt.Errorf("%s: %v maior que %v", at, n, max)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lcovEndCol é a coluna final dos blocos criados a partir de linhas DA,
+// que não informam colunas: o bloco cobre a linha inteira
+const lcovEndCol = 1 << 16
+
+// LCOVOptions configura o LCOVGenerator
+type LCOVOptions struct {
+	// Sources, quando informado, torna os caminhos SF relativos à raiz do
+	// módulo
+	Sources *SourceResolver
+	// Functions gera os registros FN/FNDA (ver AnalyzeFunctions)
+	Functions []FunctionCoverage
+}
+
+// LCOVGenerator converte a cobertura para um tracefile LCOV, como o usado
+// por genhtml, Codecov e extensões de IDE
+type LCOVGenerator struct {
+	coverage *ProjectCoverage
+	opts     LCOVOptions
+}
+
+// NewLCOVGenerator cria um gerador de tracefile LCOV
+func NewLCOVGenerator(coverage *ProjectCoverage, opts LCOVOptions) *LCOVGenerator {
+	return &LCOVGenerator{coverage: coverage, opts: opts}
+}
+
+// Generate escreve um registro SF por arquivo, com as linhas DA expandidas
+// a partir dos blocos e, se houver informação de funções, FN/FNDA
+func (lg *LCOVGenerator) Generate(w io.Writer) error {
+	functionsByFile := make(map[string][]FunctionCoverage)
+	for _, fn := range lg.opts.Functions {
+		functionsByFile[fn.FilePath] = append(functionsByFile[fn.FilePath], fn)
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, file := range lg.coverage.GetSortedFiles() {
+		fmt.Fprintf(bw, "TN:\nSF:%s\n", trimModulePath(lg.opts.Sources, file.FilePath))
+
+		functions := functionsByFile[file.FilePath]
+		hit := 0
+		for _, fn := range functions {
+			fmt.Fprintf(bw, "FN:%d,%s\n", fn.StartLine, fn.FullName())
+		}
+		for _, fn := range functions {
+			count := functionHits(fn, file.Blocks)
+			if count > 0 {
+				hit++
+			}
+			fmt.Fprintf(bw, "FNDA:%d,%s\n", count, fn.FullName())
+		}
+		if len(functions) > 0 {
+			fmt.Fprintf(bw, "FNF:%d\nFNH:%d\n", len(functions), hit)
+		}
+
+		hits := lineHits(file.Blocks)
+		lines := make([]int, 0, len(hits))
+		for line := range hits {
+			lines = append(lines, line)
+		}
+		sort.Ints(lines)
+
+		covered := 0
+		for _, line := range lines {
+			if hits[line] > 0 {
+				covered++
+			}
+			fmt.Fprintf(bw, "DA:%d,%d\n", line, hits[line])
+		}
+		fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n", len(lines), covered)
+	}
+
+	return bw.Flush()
+}
+
+// functionHits estima quantas vezes a função executou pelo contador do
+// primeiro bloco do seu corpo
+func functionHits(fn FunctionCoverage, blocks []CoverageBlock) int {
+	var first *CoverageBlock
+	for i, b := range blocks {
+		inside := (b.StartLine > fn.StartLine || (b.StartLine == fn.StartLine && b.StartCol >= fn.StartCol)) &&
+			(b.StartLine < fn.EndLine || (b.StartLine == fn.EndLine && b.StartCol < fn.EndCol))
+		if !inside {
+			continue
+		}
+		if first == nil || b.StartLine < first.StartLine || (b.StartLine == first.StartLine && b.StartCol < first.StartCol) {
+			first = &blocks[i]
+		}
+	}
+
+	if first == nil {
+		return 0
+	}
+	return first.Count
+}
+
+// trimModulePath torna o caminho relativo à raiz do módulo quando há um
+// SourceResolver com caminho de módulo
+func trimModulePath(sources *SourceResolver, p string) string {
+	if sources == nil || sources.ModulePath == "" {
+		return p
+	}
+	if p == sources.ModulePath {
+		return "."
+	}
+	if rest, ok := strings.CutPrefix(p, sources.ModulePath+"/"); ok {
+		return rest
+	}
+	return p
+}
+
+// ParseLCOV lê um tracefile LCOV e o converte em ProjectCoverage. Cada linha
+// DA vira um bloco de um statement cobrindo a linha inteira; registros de
+// funções e branches são ignorados. O resultado não tem modo definido, o que
+// permite combiná-lo com perfis Go de qualquer modo via MergeProfiles.
+func ParseLCOV(reader io.Reader) (*ProjectCoverage, error) {
+	return ParseLCOVWithOptions(reader, ParseOptions{})
+}
+
+// ParseLCOVWithOptions é ParseLCOV com os erros no idioma de opts.Language.
+// Registros inválidos geram um *ParseError com a linha.
+func ParseLCOVWithOptions(reader io.Reader, opts ParseOptions) (*ProjectCoverage, error) {
+	lang := opts.Language
+	if err := lang.Validate(); err != nil {
+		return nil, err
+	}
+	builder := newProfileBuilder("")
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	current := ""
+	lineNum := 0
+	lineError := func(cause error, format string, args ...any) error {
+		return &ParseError{Line: lineNum, Err: newLocalizedError(cause, format, args...), lang: lang}
+	}
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if line == "end_of_record" {
+			current = ""
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, lineError(ErrInvalidFormat, "registro LCOV %q", line)
+		}
+
+		switch key {
+		case "SF":
+			if value == "" {
+				return nil, lineError(ErrInvalidFormat, "SF sem caminho")
+			}
+			current = value
+		case "DA":
+			if current == "" {
+				return nil, lineError(ErrInvalidFormat, "DA fora de um registro SF")
+			}
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 {
+				return nil, lineError(ErrInvalidFormat, "DA %q", value)
+			}
+			number, err := strconv.Atoi(fields[0])
+			if err != nil || number < 1 {
+				return nil, lineError(ErrInvalidNumber, "número de linha %q", fields[0])
+			}
+			count, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil || count < 0 {
+				return nil, lineError(ErrInvalidNumber, "contador %q", fields[1])
+			}
+
+			block := CoverageBlock{
+				StartLine: number,
+				StartCol:  1,
+				EndLine:   number,
+				EndCol:    lcovEndCol,
+				NumStmt:   1,
+				Count:     int(count),
+			}
+			if err := builder.add(current, block); err != nil {
+				return nil, &ParseError{Line: lineNum, Err: err, lang: lang}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf(lang.text("erro ao ler arquivo LCOV: %w"), err)
+	}
+
+	return builder.finish(), nil
+}
@@ -0,0 +1,141 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLCOVGenerator(t *testing.T) {
+	root := writeTestModule(t)
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cov := mustParse(t, `mode: count
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 5
+example.com/demo/pkg/calc/sum.go:4.11,6.3 1 0
+example.com/demo/pkg/calc/sum.go:7.2,7.14 1 5
+`)
+	functions, err := AnalyzeFunctions(cov, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewLCOVGenerator(cov, LCOVOptions{Sources: resolver, Functions: functions}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar LCOV: %v", err)
+	}
+
+	want := `TN:
+SF:pkg/calc/sum.go
+FN:3,Sum
+FNDA:5,Sum
+FNF:1
+FNH:1
+DA:3,5
+DA:4,5
+DA:5,0
+DA:6,0
+DA:7,5
+LF:5
+LH:3
+end_of_record
+`
+	if buf.String() != want {
+		t.Errorf("LCOV inesperado:\n%s\nesperado:\n%s", buf.String(), want)
+	}
+}
+
+func TestParseLCOV(t *testing.T) {
+	input := `TN:frontend
+SF:web/src/app.ts
+FN:1,main
+FNDA:1,main
+DA:1,1
+DA:2,0
+DA:3,4
+DA:3,1
+LF:3
+LH:2
+end_of_record
+SF:web/src/util.ts
+DA:10,0
+BRDA:10,0,0,-
+end_of_record
+`
+
+	cov, err := ParseLCOV(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("erro ao ler LCOV: %v", err)
+	}
+
+	if cov.Mode != "" || len(cov.Files) != 2 {
+		t.Fatalf("cobertura inesperada: modo %q, %d arquivos", cov.Mode, len(cov.Files))
+	}
+
+	app := cov.Files["web/src/app.ts"]
+	if app.TotalStmt != 3 || app.CoveredStmt != 2 || app.FileName != "app.ts" {
+		t.Errorf("app.ts = %d/%d", app.CoveredStmt, app.TotalStmt)
+	}
+	if app.Blocks[2].Count != 5 {
+		t.Errorf("linha DA repetida deveria somar contadores, obteve %d", app.Blocks[2].Count)
+	}
+
+	util := cov.Files["web/src/util.ts"]
+	if util.TotalStmt != 1 || util.CoveredStmt != 0 {
+		t.Errorf("util.ts = %d/%d", util.CoveredStmt, util.TotalStmt)
+	}
+}
+
+func TestParseLCOVErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"DA sem SF", "DA:1,1\n"},
+		{"linha inválida", "SF:a.ts\nDA:x,1\n"},
+		{"contador inválido", "SF:a.ts\nDA:1,abc\n"},
+		{"registro sem dois pontos", "SF:a.ts\nlixo\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseLCOV(strings.NewReader(tt.input)); err == nil {
+				t.Error("esperava erro")
+			}
+		})
+	}
+}
+
+func TestLCOVRoundTripAndMerge(t *testing.T) {
+	goCov := mustParse(t, "mode: atomic\npkg/a.go:1.1,2.5 2 3\npkg/a.go:3.1,3.9 1 0\n")
+
+	var buf bytes.Buffer
+	if err := NewLCOVGenerator(goCov, LCOVOptions{}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	fromLCOV, err := ParseLCOV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := fromLCOV.Files["pkg/a.go"]
+	if file.TotalStmt != 3 || file.CoveredStmt != 2 {
+		t.Errorf("round trip = %d/%d linhas", file.CoveredStmt, file.TotalStmt)
+	}
+
+	frontend, err := ParseLCOV(strings.NewReader("SF:web/app.ts\nDA:1,1\nDA:2,0\nend_of_record\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := MergeProfiles(goCov, frontend)
+	if err != nil {
+		t.Fatalf("erro ao combinar Go e LCOV: %v", err)
+	}
+	if merged.Mode != ModeAtomic || len(merged.Files) != 2 {
+		t.Errorf("combinação inesperada: modo %q, %d arquivos", merged.Mode, len(merged.Files))
+	}
+}
@@ -0,0 +1,159 @@
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// ProfileSource é um perfil a ser carregado por LoadProfiles. Name
+// identifica o perfil nos erros e avisos (ex.: o caminho do arquivo).
+type ProfileSource struct {
+	Name   string
+	Reader io.Reader
+}
+
+// LoadOptions configura LoadProfiles
+type LoadOptions struct {
+	// Workers é o número de perfis parseados em paralelo (padrão
+	// runtime.GOMAXPROCS(0))
+	Workers int
+
+	// ParseOptions configura o parse padrão de cada perfil; o Language
+	// também traduz os erros de LoadProfiles quando Parse é informado
+	ParseOptions ParseOptions
+
+	// Parse, quando informado, substitui ParseCoverageFileWithOptions (ex.:
+	// para aceitar também LCOV ou JSON). É chamado concorrentemente.
+	Parse func(io.Reader) (*ProjectCoverage, []*ParseError, error)
+
+	// OnWarning recebe os avisos do modo ValidateLenient. É chamado na
+	// goroutine de LoadProfiles, na ordem dos perfis.
+	OnWarning func(source string, warning *ParseError)
+}
+
+// LoadProfiles parseia os perfis em paralelo com um pool de workers e os
+// combina como MergeProfiles. O resultado não depende da ordem em que os
+// workers terminam: os perfis são combinados na ordem de sources. Um erro
+// cancela apenas os perfis seguintes, então o erro retornado é sempre o do
+// primeiro perfil de sources que falha; cancelar ctx interrompe a leitura e
+// retorna ctx.Err().
+func LoadProfiles(ctx context.Context, sources []ProfileSource, opts LoadOptions) (*ProjectCoverage, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(sources))
+
+	parse := opts.Parse
+	if parse == nil {
+		parse = func(r io.Reader) (*ProjectCoverage, []*ParseError, error) {
+			return ParseCoverageFileWithOptions(r, opts.ParseOptions)
+		}
+	}
+
+	// Cada perfil tem o próprio contexto para que um erro cancele só os
+	// perfis seguintes; os anteriores terminam e podem ter um erro que vem
+	// antes em sources
+	contexts := make([]context.Context, len(sources))
+	cancels := make([]context.CancelFunc, len(sources))
+	for i := range sources {
+		contexts[i], cancels[i] = context.WithCancel(ctx)
+	}
+	feedCtx, stopFeed := context.WithCancel(ctx)
+	defer func() {
+		stopFeed()
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	var mu sync.Mutex
+	failed := len(sources) // menor índice com erro
+	fail := func(i int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if i < failed {
+			for _, cancel := range cancels[i+1 : failed] {
+				cancel()
+			}
+			failed = i
+		}
+		stopFeed()
+	}
+
+	profiles := make([]*ProjectCoverage, len(sources))
+	warnings := make([][]*ParseError, len(sources))
+	errs := make([]error, len(sources))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				reader := &contextReader{ctx: contexts[i], reader: sources[i].Reader}
+				profile, warns, err := parse(reader)
+				if err != nil {
+					errs[i] = fmt.Errorf(opts.ParseOptions.Language.text("erro ao parsear %s: %w"), sources[i].Name, err)
+					fail(i)
+					continue
+				}
+				profiles[i], warnings[i] = profile, warns
+			}
+		}()
+	}
+
+	// Depois de um erro só restam perfis seguintes a ele, que não são lidos
+feed:
+	for i := range sources {
+		select {
+		case jobs <- i:
+		case <-feedCtx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Os perfis antes do primeiro erro nunca são cancelados, então ele é
+	// sempre um erro real
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.OnWarning != nil {
+		for i, warns := range warnings {
+			for _, warning := range warns {
+				opts.OnWarning(sources[i].Name, warning)
+			}
+		}
+	}
+
+	merged, err := MergeProfiles(profiles...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao combinar perfis: %w", err)
+	}
+	return merged, nil
+}
+
+// contextReader interrompe a leitura quando o contexto é cancelado
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (cr *contextReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.reader.Read(p)
+}
@@ -0,0 +1,190 @@
+package coverage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// shardProfiles gera perfis no modo count que se sobrepõem, como shards de CI
+func shardProfiles(n int) []string {
+	profiles := make([]string, n)
+	for s := range n {
+		var sb strings.Builder
+		sb.WriteString("mode: count\n")
+		for f := range 10 {
+			for b := range 20 {
+				fmt.Fprintf(&sb, "example.com/p/pkg%d/file.go:%d.1,%d.10 %d %d\n", (s+f)%15, b*2+1, b*2+2, b%3+1, (s*b+f)%4)
+			}
+		}
+		profiles[s] = sb.String()
+	}
+	return profiles
+}
+
+func sources(profiles []string) []ProfileSource {
+	sources := make([]ProfileSource, len(profiles))
+	for i, profile := range profiles {
+		sources[i] = ProfileSource{Name: fmt.Sprintf("shard%d.out", i), Reader: strings.NewReader(profile)}
+	}
+	return sources
+}
+
+func TestLoadProfilesDeterministic(t *testing.T) {
+	profiles := shardProfiles(24)
+
+	parsed := make([]*ProjectCoverage, len(profiles))
+	for i, profile := range profiles {
+		parsed[i] = mustParse(t, profile)
+	}
+	want, err := MergeProfiles(parsed...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, workers := range []int{0, 1, 3, 8, 64} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			got, err := LoadProfiles(context.Background(), sources(profiles), LoadOptions{Workers: workers})
+			if err != nil {
+				t.Fatalf("erro ao carregar: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Error("resultado difere da combinação sequencial")
+			}
+		})
+	}
+}
+
+func TestLoadProfilesEmpty(t *testing.T) {
+	cov, err := LoadProfiles(context.Background(), nil, LoadOptions{})
+	if err != nil || len(cov.Files) != 0 {
+		t.Errorf("esperado perfil vazio, obtido %+v, %v", cov, err)
+	}
+}
+
+func TestLoadProfilesErrors(t *testing.T) {
+	profiles := shardProfiles(6)
+	profiles[3] = "mode: count\nlixo\n"
+
+	_, err := LoadProfiles(context.Background(), sources(profiles), LoadOptions{Workers: 4})
+	if err == nil || !strings.Contains(err.Error(), "shard3.out") {
+		t.Errorf("esperado erro identificando shard3.out, obtido %v", err)
+	}
+
+	mixed := []string{"mode: set\na.go:1.1,2.1 1 1\n", "mode: count\na.go:1.1,2.1 1 1\n"}
+	_, err = LoadProfiles(context.Background(), sources(mixed), LoadOptions{})
+	if !errors.Is(err, ErrMixedModes) {
+		t.Errorf("esperado ErrMixedModes, obtido %v", err)
+	}
+}
+
+// slowReader espera antes de começar a ler
+type slowReader struct {
+	delay  time.Duration
+	reader io.Reader
+}
+
+func (sr *slowReader) Read(p []byte) (int, error) {
+	time.Sleep(sr.delay)
+	sr.delay = 0
+	return sr.reader.Read(p)
+}
+
+// TestLoadProfilesFirstError verifica que o erro retornado é o do primeiro
+// perfil com erro, mesmo quando um perfil seguinte falha antes
+func TestLoadProfilesFirstError(t *testing.T) {
+	for range 5 {
+		sources := []ProfileSource{
+			// O cabeçalho demora a chegar e a linha inválida vem numa segunda leitura
+			{Name: "lento.out", Reader: &slowReader{
+				delay:  20 * time.Millisecond,
+				reader: io.MultiReader(strings.NewReader("mode: count\n"), strings.NewReader("lixo\n")),
+			}},
+			{Name: "rapido.out", Reader: strings.NewReader("mode: count\nlixo\n")},
+			{Name: "infinito.out", Reader: &endlessProfile{}},
+		}
+		_, err := LoadProfiles(context.Background(), sources, LoadOptions{Workers: 3})
+		if err == nil || !strings.Contains(err.Error(), "lento.out") {
+			t.Fatalf("esperado erro de lento.out, obtido %v", err)
+		}
+	}
+}
+
+func TestLoadProfilesWarnings(t *testing.T) {
+	profiles := []string{
+		"mode: set\na.go:1.1,2.1 1 1\nlixo\n",
+		"mode: set\nb.go:1.1,2.1 1 x\nb.go:3.1,4.1 1 1\n",
+	}
+
+	var got []string
+	opts := LoadOptions{
+		Workers:      2,
+		ParseOptions: ParseOptions{Validation: ValidateLenient},
+		OnWarning: func(source string, warning *ParseError) {
+			got = append(got, fmt.Sprintf("%s:%d", source, warning.Line))
+		},
+	}
+	cov, err := LoadProfiles(context.Background(), sources(profiles), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(got, ",") != "shard0.out:3,shard1.out:2" {
+		t.Errorf("avisos = %v", got)
+	}
+	if len(cov.Files) != 2 {
+		t.Errorf("esperado 2 arquivos, obtido %d", len(cov.Files))
+	}
+}
+
+// endlessProfile produz um perfil que nunca termina
+type endlessProfile struct {
+	header bool
+	line   int
+}
+
+func (ep *endlessProfile) Read(p []byte) (int, error) {
+	if !ep.header {
+		ep.header = true
+		return copy(p, "mode: count\n"), nil
+	}
+	ep.line++
+	return copy(p, fmt.Sprintf("a.go:%d.1,%d.5 1 1\n", ep.line, ep.line)), nil
+}
+
+func TestLoadProfilesCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	sources := []ProfileSource{
+		{Name: "infinito", Reader: &endlessProfile{}},
+		{Name: "normal", Reader: strings.NewReader("mode: count\nb.go:1.1,2.1 1 1\n")},
+	}
+	_, err := LoadProfiles(ctx, sources, LoadOptions{Workers: 2})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("esperado context.DeadlineExceeded, obtido %v", err)
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, err = LoadProfiles(canceled, []ProfileSource{{Name: "a", Reader: io.LimitReader(&endlessProfile{}, 1<<20)}}, LoadOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("esperado context.Canceled, obtido %v", err)
+	}
+}
+
+func BenchmarkLoadProfiles(b *testing.B) {
+	profiles := shardProfiles(32)
+	for _, workers := range []int{1, 0} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				LoadProfiles(context.Background(), sources(profiles), LoadOptions{Workers: workers})
+			}
+		})
+	}
+}
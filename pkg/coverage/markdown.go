@@ -0,0 +1,172 @@
+package coverage
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Valores padrão do MarkdownGenerator
+const (
+	defaultMarkdownTopFiles = 10
+	// GitHubCommentLimit é o tamanho máximo, em caracteres, de um comentário
+	// de issue ou pull request no GitHub
+	GitHubCommentLimit = 65536
+)
+
+// MarkdownOptions configura o MarkdownGenerator
+type MarkdownOptions struct {
+	// TopFiles é o número de arquivos na tabela dos piores arquivos
+	// (padrão 10; negativo omite a tabela)
+	TopFiles int
+	// MaxLength limita o tamanho da saída em caracteres (padrão
+	// GitHubCommentLimit). Pacotes que não cabem são omitidos com um aviso.
+	MaxLength int
+	// Buckets define o emoji de cada faixa de cobertura (padrão
+	// DefaultCoverageBuckets)
+	Buckets CoverageBuckets
+	// Language é o idioma dos textos e da formatação de números (padrão
+	// DefaultLanguage)
+	Language Language
+}
+
+// MarkdownGenerator gera um resumo da cobertura em Markdown, pronto para
+// comentários de pull request
+type MarkdownGenerator struct {
+	coverage *ProjectCoverage
+	opts     MarkdownOptions
+}
+
+// NewMarkdownGenerator cria um gerador de Markdown
+func NewMarkdownGenerator(coverage *ProjectCoverage, opts MarkdownOptions) *MarkdownGenerator {
+	if opts.TopFiles == 0 {
+		opts.TopFiles = defaultMarkdownTopFiles
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = GitHubCommentLimit
+	}
+	return &MarkdownGenerator{coverage: coverage, opts: opts}
+}
+
+// Generate escreve o resumo Markdown no writer
+func (mg *MarkdownGenerator) Generate(w io.Writer) error {
+	if err := mg.opts.Buckets.Validate(); err != nil {
+		return err
+	}
+	if err := mg.opts.Language.Validate(); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	mg.writeTotals(&sb)
+	mg.writeWorstFiles(&sb)
+
+	// Cada pacote é uma linha da seção; o cabeçalho entra no tamanho do
+	// primeiro
+	lang := mg.opts.Language
+	packages := markdownSection{header: fmt.Sprintf("\n### %s\n\n", lang.text("Pacotes"))}
+	for _, pkg := range mg.coverage.GetPackages() {
+		packages.rows = append(packages.rows, markdownRow{text: mg.packageDetails(pkg), items: 1})
+	}
+	if err := writeMarkdownSections(&sb, []markdownSection{packages}, mg.opts.MaxLength, lang, mg.omittedPackagesNote); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (mg *MarkdownGenerator) writeTotals(sb *strings.Builder) {
+	total := mg.coverage.GetTotalCoverage()
+	totalStmt, coveredStmt := 0, 0
+	for _, file := range mg.coverage.Files {
+		totalStmt += file.TotalStmt
+		coveredStmt += file.CoveredStmt
+	}
+
+	lang := mg.opts.Language
+	fmt.Fprintf(sb, "## %s %s\n\n", mg.emoji(total), lang.tr("Cobertura: %s", lang.percent(total)))
+	sb.WriteString(lang.tr("**%s/%s** statements cobertos em %s arquivos de %s pacotes",
+		lang.number(coveredStmt), lang.number(totalStmt), lang.number(len(mg.coverage.Files)), lang.number(len(mg.coverage.GetPackages()))))
+	if mg.coverage.Mode != "" {
+		sb.WriteString(lang.tr(" · modo `%s`", mg.coverage.Mode))
+	}
+	sb.WriteString("\n")
+}
+
+// writeWorstFiles lista os arquivos com menor cobertura. Arquivos totalmente
+// cobertos ou sem statements não entram na tabela.
+func (mg *MarkdownGenerator) writeWorstFiles(sb *strings.Builder) {
+	if mg.opts.TopFiles < 0 {
+		return
+	}
+
+	var files []*FileCoverage
+	for _, file := range mg.coverage.Files {
+		if file.TotalStmt > 0 && file.CoveredStmt < file.TotalStmt {
+			files = append(files, file)
+		}
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(sb, "\n%s\n", mg.opts.Language.text("🎉 Todos os arquivos estão 100% cobertos."))
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Coverage != files[j].Coverage {
+			return files[i].Coverage < files[j].Coverage
+		}
+		missedI := files[i].TotalStmt - files[i].CoveredStmt
+		missedJ := files[j].TotalStmt - files[j].CoveredStmt
+		if missedI != missedJ {
+			return missedI > missedJ
+		}
+		return files[i].FilePath < files[j].FilePath
+	})
+	if len(files) > mg.opts.TopFiles {
+		files = files[:mg.opts.TopFiles]
+	}
+
+	lang := mg.opts.Language
+	fmt.Fprintf(sb, "\n### %s\n\n", lang.text("Arquivos com menor cobertura"))
+	fmt.Fprintf(sb, "| | %s | %s | %s | %s |\n", lang.text("Arquivo"), lang.text("Cobertura"), lang.text("Statements"), lang.text("Sem cobertura"))
+	sb.WriteString("|---|---|---:|---:|---:|\n")
+	for _, file := range files {
+		fmt.Fprintf(sb, "| %s | `%s` | %s | %s/%s | %s |\n",
+			mg.emoji(file.Coverage), escapeMarkdownCell(file.FilePath), lang.percent(file.Coverage),
+			lang.number(file.CoveredStmt), lang.number(file.TotalStmt), lang.number(file.TotalStmt-file.CoveredStmt))
+	}
+}
+
+// packageDetails gera o bloco <details> recolhível de um pacote
+func (mg *MarkdownGenerator) packageDetails(pkg *PackageCoverage) string {
+	var sb strings.Builder
+	lang := mg.opts.Language
+
+	fmt.Fprintf(&sb, "<details><summary>%s <code>%s</code> — %s (%s/%s)</summary>\n\n",
+		mg.emoji(pkg.Coverage), html.EscapeString(pkg.ImportPath), lang.percent(pkg.Coverage), lang.number(pkg.CoveredStmt), lang.number(pkg.TotalStmt))
+	fmt.Fprintf(&sb, "| | %s | %s | %s |\n", lang.text("Arquivo"), lang.text("Cobertura"), lang.text("Statements"))
+	sb.WriteString("|---|---|---:|---:|\n")
+	for _, file := range pkg.Files {
+		fmt.Fprintf(&sb, "| %s | `%s` | %s | %s/%s |\n",
+			mg.emoji(file.Coverage), escapeMarkdownCell(file.FileName), lang.percent(file.Coverage),
+			lang.number(file.CoveredStmt), lang.number(file.TotalStmt))
+	}
+	sb.WriteString("\n</details>\n")
+
+	return sb.String()
+}
+
+func (mg *MarkdownGenerator) omittedPackagesNote(count int) string {
+	if count <= 0 {
+		return ""
+	}
+	return "\n> " + mg.opts.Language.plural(count, "⚠️ %d pacote omitido para respeitar o limite de tamanho do comentário.", "⚠️ %d pacotes omitidos para respeitar o limite de tamanho do comentário.") + "\n"
+}
+
+// emoji retorna o emoji da faixa de cobertura do valor
+func (mg *MarkdownGenerator) emoji(coverage float64) string {
+	return mg.opts.Buckets.Find(coverage).emoji()
+}
@@ -0,0 +1,152 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMarkdownGenerator(t *testing.T) {
+	cov := mustParse(t, `mode: set
+github.com/user/project/main.go:5.13,7.2 4 1
+github.com/user/project/main.go:9.13,11.2 1 0
+github.com/user/project/pkg/util.go:3.20,5.2 1 0
+github.com/user/project/pkg/util.go:6.20,8.2 1 1
+github.com/user/project/pkg/done.go:3.20,5.2 2 1
+`)
+
+	var buf bytes.Buffer
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar Markdown: %v", err)
+	}
+	md := buf.String()
+
+	for _, want := range []string{
+		"## 🟡 Cobertura: 77,8%\n",
+		"**7/9** statements cobertos em 3 arquivos de 2 pacotes · modo `set`",
+		"| 🟠 | `github.com/user/project/pkg/util.go` | 50,0% | 1/2 | 1 |\n",
+		"<details><summary>🟢 <code>github.com/user/project</code> — 80,0% (4/5)</summary>",
+		"| 🟢 | `done.go` | 100,0% | 2/2 |\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown não contém %q:\n%s", want, md)
+		}
+	}
+
+	// Piores arquivos primeiro; arquivos 100% cobertos ficam de fora da tabela
+	worst := md[strings.Index(md, "### Arquivos com menor cobertura"):strings.Index(md, "### Pacotes")]
+	if strings.Index(worst, "util.go") > strings.Index(worst, "main.go") || strings.Contains(worst, "done.go") {
+		t.Errorf("tabela de piores arquivos inesperada:\n%s", worst)
+	}
+}
+
+func TestMarkdownTopFiles(t *testing.T) {
+	var profile strings.Builder
+	profile.WriteString("mode: set\n")
+	for i := range 5 {
+		fmt.Fprintf(&profile, "example.com/p/f%d.go:1.1,2.1 1 0\n", i)
+	}
+	cov := mustParse(t, profile.String())
+
+	var buf bytes.Buffer
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{TopFiles: 2}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	worst := strings.SplitN(buf.String(), "### Pacotes", 2)[0]
+	if got := strings.Count(worst, "| 🔴 |"); got != 2 {
+		t.Errorf("esperado 2 arquivos na tabela, obtido %d:\n%s", got, worst)
+	}
+
+	buf.Reset()
+	NewMarkdownGenerator(cov, MarkdownOptions{TopFiles: -1}).Generate(&buf)
+	if strings.Contains(buf.String(), "menor cobertura") {
+		t.Error("TopFiles negativo deveria omitir a tabela")
+	}
+}
+
+func TestMarkdownSizeLimit(t *testing.T) {
+	var profile strings.Builder
+	profile.WriteString("mode: count\n")
+	for i := range 3000 {
+		fmt.Fprintf(&profile, "example.com/projeto/pacote_%04d/arquivo_com_nome_longo.go:1.1,2.1 1 %d\n", i, i%2)
+	}
+	cov := mustParse(t, profile.String())
+
+	var buf bytes.Buffer
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	md := buf.String()
+
+	if n := utf8.RuneCountInString(md); n > GitHubCommentLimit {
+		t.Errorf("Markdown com %d caracteres excede o limite do GitHub", n)
+	}
+	if !strings.Contains(md, "omitido") {
+		t.Error("esperado aviso de pacotes omitidos")
+	}
+	if strings.Count(md, "<details>") != strings.Count(md, "</details>") {
+		t.Error("bloco <details> truncado")
+	}
+
+	// Um limite menor que o cabeçalho é um erro
+	err := NewMarkdownGenerator(cov, MarkdownOptions{MaxLength: 100}).Generate(&buf)
+	if err == nil {
+		t.Error("esperado erro para limite menor que o cabeçalho")
+	}
+}
+
+// TestMarkdownSizeLimitBoundary testa todos os limites em torno do tamanho
+// da saída, com 10 pacotes para que o aviso mude de um para dois dígitos
+func TestMarkdownSizeLimitBoundary(t *testing.T) {
+	var profile strings.Builder
+	profile.WriteString("mode: set\n")
+	for i := range 10 {
+		fmt.Fprintf(&profile, "example.com/p/pkg%d/a.go:1.1,2.1 1 %d\n", i, i%2)
+	}
+	cov := mustParse(t, profile.String())
+
+	generate := func(maxLength int) (string, error) {
+		var buf bytes.Buffer
+		err := NewMarkdownGenerator(cov, MarkdownOptions{TopFiles: -1, MaxLength: maxLength}).Generate(&buf)
+		return buf.String(), err
+	}
+	full, err := generate(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullLength := utf8.RuneCountInString(full)
+
+	for limit := 100; limit <= fullLength+1; limit++ {
+		md, err := generate(limit)
+		if err != nil {
+			continue
+		}
+		if n := utf8.RuneCountInString(md); n > limit {
+			t.Fatalf("limite %d: Markdown com %d caracteres", limit, n)
+		}
+		if limit >= fullLength && md != full {
+			t.Fatalf("limite %d: saída truncada com %d caracteres", limit, utf8.RuneCountInString(md))
+		}
+		if limit < fullLength && !strings.Contains(md, "omitido") {
+			t.Fatalf("limite %d: saída truncada sem aviso:\n%s", limit, md)
+		}
+	}
+}
+
+func TestMarkdownEscaping(t *testing.T) {
+	cov := mustParse(t, "mode: set\nexample.com/<a>|b/x`y.go:1.1,2.1 1 0\n")
+
+	var buf bytes.Buffer
+	if err := NewMarkdownGenerator(cov, MarkdownOptions{}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+	md := buf.String()
+	if !strings.Contains(md, "`example.com/<a>\\|b/x'y.go`") {
+		t.Errorf("caminho não escapado na tabela:\n%s", md)
+	}
+	if !strings.Contains(md, "<code>example.com/&lt;a&gt;|b</code>") {
+		t.Errorf("HTML não escapado no resumo:\n%s", md)
+	}
+}
@@ -0,0 +1,185 @@
+package coverage
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+)
+
+// Modos de cobertura gerados por go test -covermode
+const (
+	ModeSet    = "set"
+	ModeCount  = "count"
+	ModeAtomic = "atomic"
+)
+
+// ErrMixedModes indica a tentativa de combinar perfis com modos diferentes
+var ErrMixedModes = errors.New("modos de cobertura incompatíveis")
+
+// blockKey identifica um bloco pela sua posição no arquivo
+type blockKey struct {
+	StartLine, StartCol, EndLine, EndCol int
+}
+
+func keyOf(block CoverageBlock) blockKey {
+	return blockKey{block.StartLine, block.StartCol, block.EndLine, block.EndCol}
+}
+
+// profileBuilder acumula blocos de um ou mais perfis, combinando blocos
+// repetidos na mesma posição de acordo com o modo de cobertura
+type profileBuilder struct {
+	coverage *ProjectCoverage
+	index    map[string]map[blockKey]int // arquivo -> posição -> índice em Blocks
+}
+
+func newProfileBuilder(mode string) *profileBuilder {
+	return &profileBuilder{
+		coverage: &ProjectCoverage{
+			Mode:  mode,
+			Files: make(map[string]*FileCoverage),
+		},
+		index: make(map[string]map[blockKey]int),
+	}
+}
+
+// setMode define o modo do resultado, rejeitando modos diferentes.
+// Perfis sem modo declarado são compatíveis com qualquer outro.
+func (b *profileBuilder) setMode(mode string) error {
+	switch {
+	case mode == "" || mode == b.coverage.Mode:
+		return nil
+	case b.coverage.Mode == "":
+		b.coverage.Mode = mode
+		return nil
+	default:
+		return fmt.Errorf("%w: %s e %s", ErrMixedModes, b.coverage.Mode, mode)
+	}
+}
+
+// add adiciona um bloco ao arquivo, combinando-o com o bloco já existente
+// na mesma posição
+func (b *profileBuilder) add(filePath string, block CoverageBlock) error {
+	file, ok := b.coverage.Files[filePath]
+	if !ok {
+		file = &FileCoverage{
+			FilePath: filePath,
+			FileName: filepath.Base(filePath),
+			Blocks:   []CoverageBlock{},
+		}
+		b.coverage.Files[filePath] = file
+		b.index[filePath] = make(map[blockKey]int)
+	}
+
+	key := keyOf(block)
+	i, exists := b.index[filePath][key]
+	if !exists {
+		b.index[filePath][key] = len(file.Blocks)
+		file.Blocks = append(file.Blocks, block)
+		return nil
+	}
+
+	existing := &file.Blocks[i]
+	if existing.NumStmt != block.NumStmt {
+		return newLocalizedError(nil, "%s:%d.%d,%d.%d: número de statements divergente (%d e %d)",
+			filePath, key.StartLine, key.StartCol, key.EndLine, key.EndCol, existing.NumStmt, block.NumStmt)
+	}
+	existing.Count = mergeCounts(b.coverage.Mode, existing.Count, block.Count)
+	return nil
+}
+
+// addCoverage adiciona todos os blocos de outro perfil
+func (b *profileBuilder) addCoverage(other *ProjectCoverage) error {
+	if err := b.setMode(other.Mode); err != nil {
+		return err
+	}
+	for filePath, file := range other.Files {
+		for _, block := range file.Blocks {
+			if err := b.add(filePath, block); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// finish ordena os blocos e recalcula os totais de cada arquivo
+func (b *profileBuilder) finish() *ProjectCoverage {
+	for _, file := range b.coverage.Files {
+		file.recalculate()
+	}
+	return b.coverage
+}
+
+// mergeCounts combina os contadores de um mesmo bloco: no modo set o
+// resultado indica apenas se o bloco executou; nos modos count e atomic os
+// contadores são somados
+func mergeCounts(mode string, a, b int) int {
+	if mode == ModeSet {
+		if a > 0 || b > 0 {
+			return 1
+		}
+		return 0
+	}
+	return a + b
+}
+
+// recalculate ordena os blocos por posição e recalcula os totais do arquivo
+func (fc *FileCoverage) recalculate() {
+	sort.SliceStable(fc.Blocks, func(i, j int) bool {
+		a, b := fc.Blocks[i], fc.Blocks[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		if a.StartCol != b.StartCol {
+			return a.StartCol < b.StartCol
+		}
+		if a.EndLine != b.EndLine {
+			return a.EndLine < b.EndLine
+		}
+		return a.EndCol < b.EndCol
+	})
+
+	fc.TotalStmt = 0
+	fc.CoveredStmt = 0
+	for _, block := range fc.Blocks {
+		fc.TotalStmt += block.NumStmt
+		if block.Count > 0 {
+			fc.CoveredStmt += block.NumStmt
+		}
+	}
+
+	fc.Coverage = 0
+	if fc.TotalStmt > 0 {
+		fc.Coverage = float64(fc.CoveredStmt) / float64(fc.TotalStmt) * 100
+	}
+}
+
+// MergeProfiles combina vários perfis em um novo ProjectCoverage sem alterar
+// os perfis de entrada. Blocos na mesma posição do mesmo arquivo são
+// unificados: os contadores são somados nos modos count e atomic e
+// combinados com OU no modo set. Perfis com modos diferentes retornam
+// ErrMixedModes.
+func MergeProfiles(profiles ...*ProjectCoverage) (*ProjectCoverage, error) {
+	builder := newProfileBuilder("")
+	for _, profile := range profiles {
+		if profile == nil {
+			continue
+		}
+		if err := builder.addCoverage(profile); err != nil {
+			return nil, err
+		}
+	}
+	return builder.finish(), nil
+}
+
+// Merge incorpora os blocos de other nesta cobertura com a mesma semântica
+// de MergeProfiles. Em caso de erro a cobertura não é alterada.
+func (pc *ProjectCoverage) Merge(other *ProjectCoverage) error {
+	merged, err := MergeProfiles(pc, other)
+	if err != nil {
+		return err
+	}
+	*pc = *merged
+	return nil
+}
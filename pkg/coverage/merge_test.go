@@ -0,0 +1,127 @@
+package coverage
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func mustParse(t testing.TB, input string) *ProjectCoverage {
+	t.Helper()
+	cov, err := ParseCoverageFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("erro ao parsear: %v", err)
+	}
+	return cov
+}
+
+func TestParseCoverageFileDeduplicatesBlocks(t *testing.T) {
+	cov := mustParse(t, `mode: count
+pkg/file.go:1.1,2.2 3 0
+pkg/file.go:3.1,4.2 2 1
+pkg/file.go:1.1,2.2 3 4
+`)
+
+	file := cov.Files["pkg/file.go"]
+	if len(file.Blocks) != 2 {
+		t.Fatalf("esperava 2 blocos, obteve %d", len(file.Blocks))
+	}
+	if file.Blocks[0].Count != 4 {
+		t.Errorf("contador esperado 4, obtido %d", file.Blocks[0].Count)
+	}
+	if file.TotalStmt != 5 || file.CoveredStmt != 5 {
+		t.Errorf("statements = %d/%d, esperado 5/5", file.CoveredStmt, file.TotalStmt)
+	}
+}
+
+func TestMergeProfiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		countA      string
+		countB      string
+		wantCount   int
+		wantCovered int
+	}{
+		{"set combina com OU", ModeSet, "1", "1", 1, 3},
+		{"set sem execução", ModeSet, "0", "0", 0, 0},
+		{"count soma", ModeCount, "2", "3", 5, 3},
+		{"atomic soma", ModeAtomic, "0", "7", 7, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := mustParse(t, "mode: "+tt.mode+"\npkg/a.go:1.1,2.2 3 "+tt.countA+"\n")
+			b := mustParse(t, "mode: "+tt.mode+"\npkg/a.go:1.1,2.2 3 "+tt.countB+"\npkg/b.go:1.1,2.2 1 1\n")
+
+			merged, err := MergeProfiles(a, b)
+			if err != nil {
+				t.Fatalf("erro ao combinar: %v", err)
+			}
+
+			if merged.Mode != tt.mode {
+				t.Errorf("modo = %s, esperado %s", merged.Mode, tt.mode)
+			}
+			if len(merged.Files) != 2 {
+				t.Errorf("esperava 2 arquivos, obteve %d", len(merged.Files))
+			}
+
+			file := merged.Files["pkg/a.go"]
+			if len(file.Blocks) != 1 {
+				t.Fatalf("esperava 1 bloco, obteve %d", len(file.Blocks))
+			}
+			if file.Blocks[0].Count != tt.wantCount {
+				t.Errorf("contador = %d, esperado %d", file.Blocks[0].Count, tt.wantCount)
+			}
+			if file.TotalStmt != 3 || file.CoveredStmt != tt.wantCovered {
+				t.Errorf("statements = %d/%d, esperado %d/3", file.CoveredStmt, file.TotalStmt, tt.wantCovered)
+			}
+
+			// As entradas não devem ser alteradas
+			if want, _ := strconv.Atoi(tt.countA); a.Files["pkg/a.go"].Blocks[0].Count != want {
+				t.Error("perfil de entrada foi alterado")
+			}
+		})
+	}
+}
+
+func TestMergeProfilesRejectsMixedModes(t *testing.T) {
+	a := mustParse(t, "mode: set\npkg/a.go:1.1,2.2 1 1\n")
+	b := mustParse(t, "mode: count\npkg/a.go:1.1,2.2 1 1\n")
+
+	_, err := MergeProfiles(a, b)
+	if !errors.Is(err, ErrMixedModes) {
+		t.Errorf("erro esperado ErrMixedModes, obtido %v", err)
+	}
+}
+
+func TestMergeProfilesRejectsStatementMismatch(t *testing.T) {
+	a := mustParse(t, "mode: set\npkg/a.go:1.1,2.2 1 1\n")
+	b := mustParse(t, "mode: set\npkg/a.go:1.1,2.2 2 1\n")
+
+	if _, err := MergeProfiles(a, b); err == nil {
+		t.Error("esperava erro para número de statements divergente")
+	}
+}
+
+func TestProjectCoverageMerge(t *testing.T) {
+	a := mustParse(t, "mode: count\npkg/a.go:1.1,2.2 2 0\npkg/a.go:3.1,4.2 2 1\n")
+	b := mustParse(t, "mode: count\npkg/a.go:1.1,2.2 2 1\n")
+
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("erro ao combinar: %v", err)
+	}
+
+	if total := a.GetTotalCoverage(); total != 100 {
+		t.Errorf("cobertura esperada 100%%, obtida %.2f%%", total)
+	}
+
+	c := mustParse(t, "mode: set\npkg/a.go:1.1,2.2 2 0\n")
+	if err := a.Merge(c); err == nil {
+		t.Error("esperava erro ao combinar modos diferentes")
+	}
+	if a.Mode != ModeCount || a.Files["pkg/a.go"].CoveredStmt != 4 {
+		t.Error("cobertura não deveria ser alterada após erro")
+	}
+}
@@ -0,0 +1,347 @@
+package coverage
+
+// catalogs traduz as mensagens em português (o idioma de origem) para os
+// demais idiomas. Mensagens sem tradução são exibidas em português.
+var catalogs = map[Language]map[string]string{
+	LanguageEnglish: {
+		// Relatórios
+		DefaultReportTitle:                  "Test Coverage Report",
+		"Excelente":                         "Excellent",
+		"Bom":                               "Good",
+		"Regular":                           "Fair",
+		"Fraco":                             "Poor",
+		"Cobertura Total: %s":               "Total Coverage: %s",
+		"Cobertura: %s":                     "Coverage: %s",
+		"Cobertura":                         "Coverage",
+		"Cobertura do Patch":                "Patch Coverage",
+		"Buscar arquivo...":                 "Search file...",
+		"Buscar pacote...":                  "Search package...",
+		"Nome":                              "Name",
+		"Total de Arquivos":                 "Total Files",
+		"Linhas Cobertas":                   "Covered Lines",
+		"de %s":                             "of %s",
+		"Modo":                              "Mode",
+		"Projeto":                           "Project",
+		"Pacote":                            "Package",
+		"Pacotes":                           "Packages",
+		"Arquivo":                           "File",
+		"Arquivos":                          "Files",
+		"Função":                            "Function",
+		"Funções":                           "Functions",
+		"Linha":                             "Line",
+		"Statements":                        "Statements",
+		"Sem cobertura":                     "Uncovered",
+		"Variação vs. Base":                 "Change vs. Base",
+		"%s pp":                             "%s pp",
+		"novo":                              "new",
+		"sem alteração":                     "no change",
+		"código novo":                       "new code",
+		"%d statements":                     "%d statements",
+		"**deixou de ser coberto**":         "**no longer covered**",
+		"base %s · %s novos · %s removidos": "base %s · %s added · %s removed",
+		"%s de %s statements alterados · %s arquivos":                "%s of %s changed statements · %s files",
+		"Selecione um arquivo para visualizar a cobertura":           "Select a file to view its coverage",
+		"Código-fonte não disponível para este arquivo":              "Source code not available for this file",
+		"**%s/%s** statements cobertos em %s arquivos de %s pacotes": "**%s/%s** statements covered in %s files across %s packages",
+		" · modo `%s`":                             " · mode `%s`",
+		"Arquivos com menor cobertura":             "Files with the lowest coverage",
+		"🎉 Todos os arquivos estão 100% cobertos.": "🎉 All files are 100% covered.",
+		"⚠️ %d pacotes omitidos para respeitar o limite de tamanho do comentário.": "⚠️ %d packages omitted to respect the comment size limit.",
+		"⚠️ %d pacote omitido para respeitar o limite de tamanho do comentário.":   "⚠️ %d package omitted to respect the comment size limit.",
+		"⚠️ %d itens omitidos para respeitar o limite de tamanho do comentário.":   "⚠️ %d items omitted to respect the comment size limit.",
+		"⚠️ %d item omitido para respeitar o limite de tamanho do comentário.":     "⚠️ %d item omitted to respect the comment size limit.",
+		"Blocos novos sem cobertura (%d)":                                          "New uncovered blocks (%d)",
+		"… e mais %d blocos":                                                       "… and %d more blocks",
+		"… e mais %d bloco":                                                        "… and %d more block",
+		"resumo Markdown excede o limite de %s caracteres":                         "Markdown summary exceeds the limit of %s characters",
+
+		// Limites
+		"cobertura total %s abaixo do mínimo de %s":        "total coverage %s below the minimum of %s",
+		"cobertura do patch %s abaixo do mínimo de %s":     "patch coverage %s below the minimum of %s",
+		"pacote %s: %s abaixo do mínimo de %s (regra %q)":  "package %s: %s below the minimum of %s (rule %q)",
+		"arquivo %s: %s abaixo do mínimo de %s (regra %q)": "file %s: %s below the minimum of %s (rule %q)",
+		"❌ Cobertura abaixo do mínimo (%d violações):":     "❌ Coverage below the minimum (%d violations):",
+		"❌ Cobertura abaixo do mínimo (%d violação):":      "❌ Coverage below the minimum (%d violation):",
+		"Cobertura do patch: %s (%s/%s statements)":        "Patch coverage: %s (%s/%s statements)",
+		" — linhas sem cobertura: %s":                      " — uncovered lines: %s",
+
+		// Parse de perfis
+		"modo de cobertura inválido":                                "invalid coverage mode",
+		"formato inválido":                                          "invalid format",
+		"número inválido":                                           "invalid number",
+		"faixa inválida":                                            "invalid range",
+		"linha %d, coluna %d: %s":                                   "line %d, column %d: %s",
+		"linha %d: %s":                                              "line %d: %s",
+		"coluna %d: %s":                                             "column %d: %s",
+		"arquivo de cobertura vazio":                                "empty coverage file",
+		"erro ao ler arquivo: %w":                                   "error reading file: %w",
+		"erro ao parsear %s: %w":                                    "error parsing %s: %w",
+		"cabeçalho mode: ausente":                                   "missing mode: header",
+		"%q (esperado set, count ou atomic)":                        "%q (expected set, count or atomic)",
+		"esperado arquivo:posição statements contador":              "expected file:position statements count",
+		"formato de arquivo inválido":                               "invalid file format",
+		"formato de posição inválido %q":                            "invalid position format %q",
+		"formato de coordenadas inválido %q":                        "invalid coordinates format %q",
+		"número de statements %q":                                   "number of statements %q",
+		"contador %q":                                               "count %q",
+		"linha %q":                                                  "line %q",
+		"coluna %q":                                                 "column %q",
+		"início %d.%d fora do arquivo":                              "start %d.%d outside the file",
+		"fim %d.%d fora do arquivo":                                 "end %d.%d outside the file",
+		"fim %d.%d antes do início %d.%d":                           "end %d.%d before start %d.%d",
+		"número de statements negativo: %d":                         "negative number of statements: %d",
+		"contador negativo: %d":                                     "negative count: %d",
+		"contador %d no modo set (esperado 0 ou 1)":                 "count %d in set mode (expected 0 or 1)",
+		"%s:%d.%d,%d.%d: número de statements divergente (%d e %d)": "%s:%d.%d,%d.%d: mismatched number of statements (%d and %d)",
+
+		// Parse de LCOV, JSON e diffs
+		"registro LCOV %q":                                   "LCOV record %q",
+		"SF sem caminho":                                     "SF without a path",
+		"DA fora de um registro SF":                          "DA outside an SF record",
+		"DA %q":                                              "DA %q",
+		"número de linha %q":                                 "line number %q",
+		"erro ao ler arquivo LCOV: %w":                       "error reading LCOV file: %w",
+		"cabeçalho de hunk %q":                               "hunk header %q",
+		"hunk sem cabeçalho de arquivo":                      "hunk without a file header",
+		"erro ao ler diff: %w":                               "error reading diff: %w",
+		"erro ao ler relatório JSON: %w":                     "error reading JSON report: %w",
+		"versão de schema não suportada: %d (suportada: %d)": "unsupported schema version: %d (supported: %d)",
+		"arquivo sem caminho no relatório JSON":              "file without a path in the JSON report",
+
+		// CLI
+		"Uso: coverage-report [opções]":                 "Usage: coverage-report [options]",
+		"Opções:":                                       "Options:",
+		"✅ Relatório gerado: %s":                        "✅ Report generated: %s",
+		"✅ Site gerado: %s":                             "✅ Site generated: %s",
+		"⚠️  filtros do código-fonte não aplicados: %v": "⚠️  source code filters not applied: %v",
+		"⚠️  cobertura por função indisponível: %v":     "⚠️  function coverage unavailable: %v",
+		"argumento inesperado: %s":                      "unexpected argument: %s",
+		"%s: valor inválido para -%s: %w":               "%s: invalid value for -%s: %w",
+		"-in e -out não podem ser vazios":               "-in and -out cannot be empty",
+		"-coverdir deve ser um diretório":               "-coverdir must be a directory",
+		"-strict e -lenient são mutuamente exclusivos":  "-strict and -lenient are mutually exclusive",
+		"-site deve ser um diretório":                   "-site must be a directory",
+		"-diff-md requer -base":                         "-diff-md requires -base",
+		"apenas uma saída pode usar stdout":             "only one output can use stdout",
+		"-min e -patch-min devem estar entre 0 e 100":   "-min and -patch-min must be between 0 and 100",
+		"-patch-min requer -patch":                      "-patch-min requires -patch",
+		"-badge-precision deve estar entre 0 e 4":       "-badge-precision must be between 0 and 4",
+		"-lang: %w":                              "-lang: %w",
+		"-buckets: limite inválido %q":           "-buckets: invalid limit %q",
+		"-buckets: %w":                           "-buckets: %w",
+		"-thresholds: %w":                        "-thresholds: %w",
+		"stdin só pode ser lido uma vez":         "stdin can only be read once",
+		"erro ao abrir arquivo de cobertura: %w": "error opening coverage file: %w",
+		"erro ao abrir arquivo de limites: %w":   "error opening thresholds file: %w",
+		"erro ao abrir diff: %w":                 "error opening diff: %w",
+		"erro ao ler diff %s: %w":                "error reading diff %s: %w",
+		"erro ao combinar %s: %w":                "error merging %s: %w",
+		"erro ao criar arquivo de saída: %w":     "error creating output file: %w",
+		"erro ao gerar relatório: %w":            "error generating report: %w",
+		"erro ao fechar arquivo de saída: %w":    "error closing output file: %w",
+
+		// Flags do CLI
+		"arquivo de configuração YAML ou JSON (padrão .coverage-report.yml, .yaml ou .json, se existir)":     "YAML or JSON configuration file (default .coverage-report.yml, .yaml or .json, if present)",
+		`arquivo de cobertura de entrada ("-" para stdin); pode ser repetido (padrão "coverage.out")`:        `input coverage file ("-" for stdin); may be repeated (default "coverage.out")`,
+		"diretório GOCOVERDIR de binários compilados com go build -cover; pode ser repetido":                 "GOCOVERDIR directory of binaries built with go build -cover; may be repeated",
+		`arquivo HTML de saída ("-" para stdout)`:                                                            `output HTML file ("-" for stdout)`,
+		"diretório raiz do módulo com o código-fonte":                                                        "module root directory with the source code",
+		"título do relatório HTML":                                                                           "HTML report title",
+		"diretório com templates *.html que redefinem blocos do HTML (header, sidebar, fileview...)":         "directory with *.html templates that redefine HTML blocks (header, sidebar, fileview...)",
+		"idioma dos relatórios e das mensagens: pt-BR, en ou es (padrão pt-BR)":                              "language of reports and messages: pt-BR, en or es (default pt-BR)",
+		"inclui apenas arquivos que casam com o glob (*, ?, **); pode ser repetido":                          "include only files matching the glob (*, ?, **); may be repeated",
+		`exclui arquivos que casam com o glob (ex.: "*.pb.go", "**/vendor/**"); pode ser repetido`:           `exclude files matching the glob (e.g. "*.pb.go", "**/vendor/**"); may be repeated`,
+		"inclui apenas arquivos que casam com a expressão regular; pode ser repetido":                        "include only files matching the regular expression; may be repeated",
+		"exclui arquivos que casam com a expressão regular; pode ser repetido":                               "exclude files matching the regular expression; may be repeated",
+		`mantém arquivos com o cabeçalho "Code generated ... DO NOT EDIT."`:                                  `keep files with the "Code generated ... DO NOT EDIT." header`,
+		"desconsidera os comentários //coverage:ignore":                                                      "disregard //coverage:ignore comments",
+		"valida modo, campos numéricos e faixas dos perfis, abortando no primeiro erro":                      "validate profile mode, numeric fields and ranges, aborting on the first error",
+		"valida os perfis como -strict, mas ignora as linhas inválidas e mostra avisos":                      "validate profiles like -strict, but skip invalid lines and show warnings",
+		"cobertura total mínima em %; abaixo dela o comando sai com código 3":                                "minimum total coverage in %; below it the command exits with code 3",
+		"arquivo JSON com limites total, por pacote e por arquivo":                                           "JSON file with total, per-package and per-file thresholds",
+		"perfil de cobertura base para comparar com -in (ex.: da branch principal)":                          "base coverage profile to compare with -in (e.g. from the main branch)",
+		`escreve o resumo Markdown da comparação com -base neste arquivo ("-" para stdout)`:                  `write the Markdown summary of the comparison with -base to this file ("-" for stdout)`,
+		`diff unificado (ex.: git diff) para calcular a cobertura das linhas alteradas ("-" para stdin)`:     `unified diff (e.g. git diff) to compute the coverage of changed lines ("-" for stdin)`,
+		"cobertura mínima em % das linhas alteradas; abaixo dela o comando sai com código 3":                 "minimum coverage in % of changed lines; below it the command exits with code 3",
+		`escreve também o relatório no formato XML Cobertura neste arquivo ("-" para stdout)`:                `also write the report in Cobertura XML format to this file ("-" for stdout)`,
+		`escreve também o tracefile LCOV neste arquivo ("-" para stdout)`:                                    `also write the LCOV tracefile to this file ("-" for stdout)`,
+		`escreve também o relatório no formato JSON versionado neste arquivo ("-" para stdout)`:              `also write the report in the versioned JSON format to this file ("-" for stdout)`,
+		`escreve também um resumo Markdown para comentários de pull request neste arquivo ("-" para stdout)`: `also write a Markdown summary for pull request comments to this file ("-" for stdout)`,
+		`escreve também um badge SVG com a cobertura total neste arquivo ("-" para stdout)`:                  `also write an SVG badge with the total coverage to this file ("-" for stdout)`,
+		"rótulo do badge SVG":                               "SVG badge label",
+		"casas decimais do percentual no badge SVG (0 a 4)": "decimal places of the percentage in the SVG badge (0 to 4)",
+		"mínimos das faixas de cobertura das cores, do maior para o menor, separados por vírgula (padrão 80,60,40)": "minimums of the coverage color buckets, from highest to lowest, comma-separated (default 80,60,40)",
+		"gera o relatório como site estático (uma página por pacote e por arquivo) neste diretório":                 "generate the report as a static site (one page per package and per file) in this directory",
+	},
+
+	LanguageSpanish: {
+		// Relatórios
+		DefaultReportTitle:                  "Informe de Cobertura de Pruebas",
+		"Excelente":                         "Excelente",
+		"Bom":                               "Buena",
+		"Regular":                           "Regular",
+		"Fraco":                             "Baja",
+		"Cobertura Total: %s":               "Cobertura Total: %s",
+		"Cobertura: %s":                     "Cobertura: %s",
+		"Cobertura":                         "Cobertura",
+		"Cobertura do Patch":                "Cobertura del Parche",
+		"Buscar arquivo...":                 "Buscar archivo...",
+		"Buscar pacote...":                  "Buscar paquete...",
+		"Nome":                              "Nombre",
+		"Total de Arquivos":                 "Total de Archivos",
+		"Linhas Cobertas":                   "Líneas Cubiertas",
+		"de %s":                             "de %s",
+		"Modo":                              "Modo",
+		"Projeto":                           "Proyecto",
+		"Pacote":                            "Paquete",
+		"Pacotes":                           "Paquetes",
+		"Arquivo":                           "Archivo",
+		"Arquivos":                          "Archivos",
+		"Função":                            "Función",
+		"Funções":                           "Funciones",
+		"Linha":                             "Línea",
+		"Statements":                        "Sentencias",
+		"Sem cobertura":                     "Sin cobertura",
+		"Variação vs. Base":                 "Variación vs. Base",
+		"%s pp":                             "%s pp",
+		"novo":                              "nuevo",
+		"sem alteração":                     "sin cambios",
+		"código novo":                       "código nuevo",
+		"%d statements":                     "%d sentencias",
+		"**deixou de ser coberto**":         "**dejó de estar cubierto**",
+		"base %s · %s novos · %s removidos": "base %s · %s nuevos · %s eliminados",
+		"%s de %s statements alterados · %s arquivos":                "%s de %s sentencias modificadas · %s archivos",
+		"Selecione um arquivo para visualizar a cobertura":           "Seleccione un archivo para ver su cobertura",
+		"Código-fonte não disponível para este arquivo":              "Código fuente no disponible para este archivo",
+		"**%s/%s** statements cobertos em %s arquivos de %s pacotes": "**%s/%s** sentencias cubiertas en %s archivos de %s paquetes",
+		" · modo `%s`":                             " · modo `%s`",
+		"Arquivos com menor cobertura":             "Archivos con menor cobertura",
+		"🎉 Todos os arquivos estão 100% cobertos.": "🎉 Todos los archivos están cubiertos al 100%.",
+		"⚠️ %d pacotes omitidos para respeitar o limite de tamanho do comentário.": "⚠️ %d paquetes omitidos para respetar el límite de tamaño del comentario.",
+		"⚠️ %d pacote omitido para respeitar o limite de tamanho do comentário.":   "⚠️ %d paquete omitido para respetar el límite de tamaño del comentario.",
+		"⚠️ %d itens omitidos para respeitar o limite de tamanho do comentário.":   "⚠️ %d elementos omitidos para respetar el límite de tamaño del comentario.",
+		"⚠️ %d item omitido para respeitar o limite de tamanho do comentário.":     "⚠️ %d elemento omitido para respetar el límite de tamaño del comentario.",
+		"Blocos novos sem cobertura (%d)":                                          "Bloques nuevos sin cobertura (%d)",
+		"… e mais %d blocos":                                                       "… y %d bloques más",
+		"… e mais %d bloco":                                                        "… y %d bloque más",
+		"resumo Markdown excede o limite de %s caracteres":                         "el resumen Markdown supera el límite de %s caracteres",
+
+		// Limites
+		"cobertura total %s abaixo do mínimo de %s":        "cobertura total %s por debajo del mínimo de %s",
+		"cobertura do patch %s abaixo do mínimo de %s":     "cobertura del parche %s por debajo del mínimo de %s",
+		"pacote %s: %s abaixo do mínimo de %s (regra %q)":  "paquete %s: %s por debajo del mínimo de %s (regla %q)",
+		"arquivo %s: %s abaixo do mínimo de %s (regra %q)": "archivo %s: %s por debajo del mínimo de %s (regla %q)",
+		"❌ Cobertura abaixo do mínimo (%d violações):":     "❌ Cobertura por debajo del mínimo (%d infracciones):",
+		"❌ Cobertura abaixo do mínimo (%d violação):":      "❌ Cobertura por debajo del mínimo (%d infracción):",
+		"Cobertura do patch: %s (%s/%s statements)":        "Cobertura del parche: %s (%s/%s sentencias)",
+		" — linhas sem cobertura: %s":                      " — líneas sin cobertura: %s",
+
+		// Parse de perfis
+		"modo de cobertura inválido":                                "modo de cobertura no válido",
+		"formato inválido":                                          "formato no válido",
+		"número inválido":                                           "número no válido",
+		"faixa inválida":                                            "rango no válido",
+		"linha %d, coluna %d: %s":                                   "línea %d, columna %d: %s",
+		"linha %d: %s":                                              "línea %d: %s",
+		"coluna %d: %s":                                             "columna %d: %s",
+		"arquivo de cobertura vazio":                                "archivo de cobertura vacío",
+		"erro ao ler arquivo: %w":                                   "error al leer el archivo: %w",
+		"erro ao parsear %s: %w":                                    "error al analizar %s: %w",
+		"cabeçalho mode: ausente":                                   "falta la cabecera mode:",
+		"%q (esperado set, count ou atomic)":                        "%q (se esperaba set, count o atomic)",
+		"esperado arquivo:posição statements contador":              "se esperaba archivo:posición sentencias contador",
+		"formato de arquivo inválido":                               "formato de archivo no válido",
+		"formato de posição inválido %q":                            "formato de posición no válido %q",
+		"formato de coordenadas inválido %q":                        "formato de coordenadas no válido %q",
+		"número de statements %q":                                   "número de sentencias %q",
+		"contador %q":                                               "contador %q",
+		"linha %q":                                                  "línea %q",
+		"coluna %q":                                                 "columna %q",
+		"início %d.%d fora do arquivo":                              "inicio %d.%d fuera del archivo",
+		"fim %d.%d fora do arquivo":                                 "fin %d.%d fuera del archivo",
+		"fim %d.%d antes do início %d.%d":                           "fin %d.%d antes del inicio %d.%d",
+		"número de statements negativo: %d":                         "número de sentencias negativo: %d",
+		"contador negativo: %d":                                     "contador negativo: %d",
+		"contador %d no modo set (esperado 0 ou 1)":                 "contador %d en modo set (se esperaba 0 o 1)",
+		"%s:%d.%d,%d.%d: número de statements divergente (%d e %d)": "%s:%d.%d,%d.%d: número de sentencias divergente (%d y %d)",
+
+		// Parse de LCOV, JSON e diffs
+		"registro LCOV %q":                                   "registro LCOV %q",
+		"SF sem caminho":                                     "SF sin ruta",
+		"DA fora de um registro SF":                          "DA fuera de un registro SF",
+		"DA %q":                                              "DA %q",
+		"número de linha %q":                                 "número de línea %q",
+		"erro ao ler arquivo LCOV: %w":                       "error al leer el archivo LCOV: %w",
+		"cabeçalho de hunk %q":                               "encabezado de hunk %q",
+		"hunk sem cabeçalho de arquivo":                      "hunk sin encabezado de archivo",
+		"erro ao ler diff: %w":                               "error al leer el diff: %w",
+		"erro ao ler relatório JSON: %w":                     "error al leer el informe JSON: %w",
+		"versão de schema não suportada: %d (suportada: %d)": "versión de esquema no soportada: %d (soportada: %d)",
+		"arquivo sem caminho no relatório JSON":              "archivo sin ruta en el informe JSON",
+
+		// CLI
+		"Uso: coverage-report [opções]":                 "Uso: coverage-report [opciones]",
+		"Opções:":                                       "Opciones:",
+		"✅ Relatório gerado: %s":                        "✅ Informe generado: %s",
+		"✅ Site gerado: %s":                             "✅ Sitio generado: %s",
+		"⚠️  filtros do código-fonte não aplicados: %v": "⚠️  filtros del código fuente no aplicados: %v",
+		"⚠️  cobertura por função indisponível: %v":     "⚠️  cobertura por función no disponible: %v",
+		"argumento inesperado: %s":                      "argumento inesperado: %s",
+		"%s: valor inválido para -%s: %w":               "%s: valor no válido para -%s: %w",
+		"-in e -out não podem ser vazios":               "-in y -out no pueden estar vacíos",
+		"-coverdir deve ser um diretório":               "-coverdir debe ser un directorio",
+		"-strict e -lenient são mutuamente exclusivos":  "-strict y -lenient son mutuamente excluyentes",
+		"-site deve ser um diretório":                   "-site debe ser un directorio",
+		"-diff-md requer -base":                         "-diff-md requiere -base",
+		"apenas uma saída pode usar stdout":             "solo una salida puede usar stdout",
+		"-min e -patch-min devem estar entre 0 e 100":   "-min y -patch-min deben estar entre 0 y 100",
+		"-patch-min requer -patch":                      "-patch-min requiere -patch",
+		"-badge-precision deve estar entre 0 e 4":       "-badge-precision debe estar entre 0 y 4",
+		"-lang: %w":                              "-lang: %w",
+		"-buckets: limite inválido %q":           "-buckets: límite no válido %q",
+		"-buckets: %w":                           "-buckets: %w",
+		"-thresholds: %w":                        "-thresholds: %w",
+		"stdin só pode ser lido uma vez":         "stdin solo puede leerse una vez",
+		"erro ao abrir arquivo de cobertura: %w": "error al abrir el archivo de cobertura: %w",
+		"erro ao abrir arquivo de limites: %w":   "error al abrir el archivo de límites: %w",
+		"erro ao abrir diff: %w":                 "error al abrir el diff: %w",
+		"erro ao ler diff %s: %w":                "error al leer el diff %s: %w",
+		"erro ao combinar %s: %w":                "error al combinar %s: %w",
+		"erro ao criar arquivo de saída: %w":     "error al crear el archivo de salida: %w",
+		"erro ao gerar relatório: %w":            "error al generar el informe: %w",
+		"erro ao fechar arquivo de saída: %w":    "error al cerrar el archivo de salida: %w",
+
+		// Flags do CLI
+		"arquivo de configuração YAML ou JSON (padrão .coverage-report.yml, .yaml ou .json, se existir)":     "archivo de configuración YAML o JSON (por defecto .coverage-report.yml, .yaml o .json, si existe)",
+		`arquivo de cobertura de entrada ("-" para stdin); pode ser repetido (padrão "coverage.out")`:        `archivo de cobertura de entrada ("-" para stdin); puede repetirse (por defecto "coverage.out")`,
+		"diretório GOCOVERDIR de binários compilados com go build -cover; pode ser repetido":                 "directorio GOCOVERDIR de binarios compilados con go build -cover; puede repetirse",
+		`arquivo HTML de saída ("-" para stdout)`:                                                            `archivo HTML de salida ("-" para stdout)`,
+		"diretório raiz do módulo com o código-fonte":                                                        "directorio raíz del módulo con el código fuente",
+		"título do relatório HTML":                                                                           "título del informe HTML",
+		"diretório com templates *.html que redefinem blocos do HTML (header, sidebar, fileview...)":         "directorio con plantillas *.html que redefinen bloques del HTML (header, sidebar, fileview...)",
+		"idioma dos relatórios e das mensagens: pt-BR, en ou es (padrão pt-BR)":                              "idioma de los informes y de los mensajes: pt-BR, en o es (por defecto pt-BR)",
+		"inclui apenas arquivos que casam com o glob (*, ?, **); pode ser repetido":                          "incluye solo los archivos que coinciden con el glob (*, ?, **); puede repetirse",
+		`exclui arquivos que casam com o glob (ex.: "*.pb.go", "**/vendor/**"); pode ser repetido`:           `excluye los archivos que coinciden con el glob (p. ej.: "*.pb.go", "**/vendor/**"); puede repetirse`,
+		"inclui apenas arquivos que casam com a expressão regular; pode ser repetido":                        "incluye solo los archivos que coinciden con la expresión regular; puede repetirse",
+		"exclui arquivos que casam com a expressão regular; pode ser repetido":                               "excluye los archivos que coinciden con la expresión regular; puede repetirse",
+		`mantém arquivos com o cabeçalho "Code generated ... DO NOT EDIT."`:                                  `mantiene los archivos con el encabezado "Code generated ... DO NOT EDIT."`,
+		"desconsidera os comentários //coverage:ignore":                                                      "ignora los comentarios //coverage:ignore",
+		"valida modo, campos numéricos e faixas dos perfis, abortando no primeiro erro":                      "valida el modo, los campos numéricos y los rangos de los perfiles, abortando en el primer error",
+		"valida os perfis como -strict, mas ignora as linhas inválidas e mostra avisos":                      "valida los perfiles como -strict, pero omite las líneas no válidas y muestra avisos",
+		"cobertura total mínima em %; abaixo dela o comando sai com código 3":                                "cobertura total mínima en %; por debajo de ella el comando termina con código 3",
+		"arquivo JSON com limites total, por pacote e por arquivo":                                           "archivo JSON con límites total, por paquete y por archivo",
+		"perfil de cobertura base para comparar com -in (ex.: da branch principal)":                          "perfil de cobertura base para comparar con -in (p. ej.: de la rama principal)",
+		`escreve o resumo Markdown da comparação com -base neste arquivo ("-" para stdout)`:                  `escribe el resumen Markdown de la comparación con -base en este archivo ("-" para stdout)`,
+		`diff unificado (ex.: git diff) para calcular a cobertura das linhas alteradas ("-" para stdin)`:     `diff unificado (p. ej.: git diff) para calcular la cobertura de las líneas modificadas ("-" para stdin)`,
+		"cobertura mínima em % das linhas alteradas; abaixo dela o comando sai com código 3":                 "cobertura mínima en % de las líneas modificadas; por debajo de ella el comando termina con código 3",
+		`escreve também o relatório no formato XML Cobertura neste arquivo ("-" para stdout)`:                `escribe también el informe en formato XML Cobertura en este archivo ("-" para stdout)`,
+		`escreve também o tracefile LCOV neste arquivo ("-" para stdout)`:                                    `escribe también el tracefile LCOV en este archivo ("-" para stdout)`,
+		`escreve também o relatório no formato JSON versionado neste arquivo ("-" para stdout)`:              `escribe también el informe en el formato JSON versionado en este archivo ("-" para stdout)`,
+		`escreve também um resumo Markdown para comentários de pull request neste arquivo ("-" para stdout)`: `escribe también un resumen Markdown para comentarios de pull request en este archivo ("-" para stdout)`,
+		`escreve também um badge SVG com a cobertura total neste arquivo ("-" para stdout)`:                  `escribe también un badge SVG con la cobertura total en este archivo ("-" para stdout)`,
+		"rótulo do badge SVG":                               "etiqueta del badge SVG",
+		"casas decimais do percentual no badge SVG (0 a 4)": "decimales del porcentaje en el badge SVG (0 a 4)",
+		"mínimos das faixas de cobertura das cores, do maior para o menor, separados por vírgula (padrão 80,60,40)": "mínimos de los rangos de cobertura de los colores, de mayor a menor, separados por comas (por defecto 80,60,40)",
+		"gera o relatório como site estático (uma página por pacote e por arquivo) neste diretório":                 "genera el informe como sitio estático (una página por paquete y por archivo) en este directorio",
+	},
+}
@@ -0,0 +1,51 @@
+package coverage
+
+import (
+	"path"
+	"sort"
+)
+
+// PackageCoverage agrega a cobertura dos arquivos de um mesmo pacote
+type PackageCoverage struct {
+	ImportPath  string
+	Files       []*FileCoverage // ordenados por caminho
+	TotalStmt   int
+	CoveredStmt int
+	Coverage    float64 // percentual de 0-100
+}
+
+// packagePath retorna o pacote (diretório) de um arquivo do perfil
+func packagePath(filePath string) string {
+	return path.Dir(filePath)
+}
+
+// GetPackages agrupa os arquivos por pacote, ordenados pelo caminho de import
+func (pc *ProjectCoverage) GetPackages() []*PackageCoverage {
+	byPath := make(map[string]*PackageCoverage)
+	packages := []*PackageCoverage{}
+
+	for _, file := range pc.GetSortedFiles() {
+		importPath := packagePath(file.FilePath)
+		pkg, ok := byPath[importPath]
+		if !ok {
+			pkg = &PackageCoverage{ImportPath: importPath}
+			byPath[importPath] = pkg
+			packages = append(packages, pkg)
+		}
+
+		pkg.Files = append(pkg.Files, file)
+		pkg.TotalStmt += file.TotalStmt
+		pkg.CoveredStmt += file.CoveredStmt
+	}
+
+	for _, pkg := range packages {
+		if pkg.TotalStmt > 0 {
+			pkg.Coverage = float64(pkg.CoveredStmt) / float64(pkg.TotalStmt) * 100
+		}
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].ImportPath < packages[j].ImportPath
+	})
+	return packages
+}
@@ -0,0 +1,47 @@
+package coverage
+
+import (
+	"errors"
+)
+
+// Causas dos erros de parse, para uso com errors.Is
+var (
+	ErrInvalidMode   = errors.New("modo de cobertura inválido")
+	ErrInvalidFormat = errors.New("formato inválido")
+	ErrInvalidNumber = errors.New("número inválido")
+	ErrInvalidRange  = errors.New("faixa inválida")
+)
+
+// ParseError é um erro de parse de um perfil de cobertura, tracefile LCOV
+// ou diff. Line e Column são 1-based; Column é o offset em bytes do campo
+// inválido na linha, ou 0 quando o erro vale para a linha inteira.
+type ParseError struct {
+	Line   int
+	Column int
+	Err    error
+
+	lang Language // idioma da mensagem (ParseOptions.Language)
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return e.lang.tr("coluna %d: %s", e.Column, localizeError(e.Err, e.lang))
+	}
+	if e.Column == 0 {
+		return e.lang.tr("linha %d: %s", e.Line, localizeError(e.Err, e.lang))
+	}
+	return e.lang.tr("linha %d, coluna %d: %s", e.Line, e.Column, localizeError(e.Err, e.lang))
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError cria um erro na coluna informada com a causa e o detalhe,
+// traduzidos no idioma de quem o reporta
+func newParseError(column int, cause error, format string, args ...any) *ParseError {
+	return &ParseError{
+		Column: column,
+		Err:    newLocalizedError(cause, format, args...),
+	}
+}
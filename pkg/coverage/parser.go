@@ -1,12 +1,11 @@
 package coverage
 
 import (
-	"bufio"
-	"fmt"
 	"io"
-	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 // CoverageBlock representa um bloco de cobertura no código
@@ -35,120 +34,228 @@ type ProjectCoverage struct {
 	Files map[string]*FileCoverage
 }
 
-// ParseCoverageFile lê e parseia um arquivo de cobertura do Go
-func ParseCoverageFile(reader io.Reader) (*ProjectCoverage, error) {
-	coverage := &ProjectCoverage{
-		Files: make(map[string]*FileCoverage),
-	}
+// Validation define o nível de validação do parse de perfis
+type Validation int
 
-	scanner := bufio.NewScanner(reader)
+const (
+	// ValidateDefault rejeita linhas mal formadas e campos não numéricos
+	ValidateDefault Validation = iota
+	// ValidateStrict também exige o cabeçalho mode: com set, count ou
+	// atomic, valores não negativos, faixas ordenadas e contadores 0 ou 1
+	// no modo set, abortando no primeiro erro
+	ValidateStrict
+	// ValidateLenient aplica as mesmas validações do modo estrito, mas
+	// ignora as linhas inválidas e as devolve como avisos
+	ValidateLenient
+)
 
-	// Ler primeira linha (modo)
-	if !scanner.Scan() {
-		return nil, fmt.Errorf("arquivo de cobertura vazio")
-	}
+// ParseOptions configura ParseCoverageFileWithOptions e os demais parsers
+// com opções (LCOV, JSON e diffs)
+type ParseOptions struct {
+	// Validation vale apenas para perfis do Go
+	Validation Validation
+	// Language é o idioma das mensagens de erro (padrão DefaultLanguage)
+	Language Language
+}
 
-	modeLine := scanner.Text()
-	if strings.HasPrefix(modeLine, "mode:") {
-		coverage.Mode = strings.TrimSpace(strings.TrimPrefix(modeLine, "mode:"))
-	}
+// ParseCoverageFile lê e parseia um arquivo de cobertura do Go. Blocos
+// repetidos na mesma posição (comuns com -coverpkg) são unificados conforme
+// o modo, como em MergeProfiles.
+func ParseCoverageFile(reader io.Reader) (*ProjectCoverage, error) {
+	coverage, _, err := ParseCoverageFileWithOptions(reader, ParseOptions{})
+	return coverage, err
+}
 
-	// Ler blocos de cobertura
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			continue
-		}
+// ParseCoverageFileWithOptions parseia um arquivo de cobertura com o nível
+// de validação informado. Os erros de linha são do tipo *ParseError; no
+// modo ValidateLenient eles são devolvidos como avisos e as linhas inválidas
+// são ignoradas.
+func ParseCoverageFileWithOptions(reader io.Reader, opts ParseOptions) (*ProjectCoverage, []*ParseError, error) {
+	scanner := NewProfileScanner(reader, opts)
 
-		block, err := parseCoverageLine(line)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao parsear linha: %s - %w", line, err)
+	var builder *profileBuilder
+	for scanner.Scan() {
+		if builder == nil {
+			builder = newProfileBuilder(scanner.Mode())
 		}
 
-		// Extrair arquivo do caminho
-		filePath := block.FilePath
-		if _, ok := coverage.Files[filePath]; !ok {
-			coverage.Files[filePath] = &FileCoverage{
-				FilePath: filePath,
-				FileName: filepath.Base(filePath),
-				Blocks:   []CoverageBlock{},
+		if err := builder.add(scanner.FilePath(), scanner.Block()); err != nil {
+			if !scanner.report(&ParseError{Column: scanner.current.stmtColumn, Err: err}) {
+				break
 			}
 		}
-
-		coverage.Files[filePath].Blocks = append(coverage.Files[filePath].Blocks, block.Block)
-		coverage.Files[filePath].TotalStmt += block.Block.NumStmt
-		if block.Block.Count > 0 {
-			coverage.Files[filePath].CoveredStmt += block.Block.NumStmt
-		}
 	}
-
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao ler arquivo: %w", err)
+		return nil, nil, err
+	}
+
+	// Perfis apenas com o cabeçalho (ou só com linhas inválidas)
+	if builder == nil {
+		builder = newProfileBuilder(scanner.Mode())
 	}
 
-	// Calcular percentuais
-	for _, file := range coverage.Files {
-		if file.TotalStmt > 0 {
-			file.Coverage = float64(file.CoveredStmt) / float64(file.TotalStmt) * 100
+	// Calcular totais e percentuais
+	return builder.finish(), scanner.Warnings(), nil
+}
+
+// parseModeLine lê o modo do cabeçalho "mode: ...". Perfis sem cabeçalho
+// têm modo vazio, o que só é aceito fora do modo estrito.
+func parseModeLine(line string, strict bool) (string, *ParseError) {
+	rest, ok := strings.CutPrefix(line, "mode:")
+	if !ok {
+		if strict {
+			return "", newParseError(1, ErrInvalidMode, "cabeçalho mode: ausente")
 		}
+		return "", nil
 	}
 
-	return coverage, nil
+	mode := strings.TrimSpace(rest)
+	if strict {
+		switch mode {
+		case ModeSet, ModeCount, ModeAtomic:
+		default:
+			column := len(line) - len(strings.TrimLeft(rest, " \t")) + 1
+			return "", newParseError(column, ErrInvalidMode, "%q (esperado set, count ou atomic)", mode)
+		}
+	}
+	return mode, nil
 }
 
 type parsedCoverageLine struct {
 	FilePath string
 	Block    CoverageBlock
+
+	// Colunas (1-based) dos campos, usadas nas mensagens de erro
+	startColumn, endColumn, stmtColumn, countColumn int
+}
+
+// lineField é um campo de uma linha do perfil com a sua coluna
+type lineField struct {
+	text   string
+	column int
 }
 
-func parseCoverageLine(line string) (*parsedCoverageLine, error) {
-	// Formato: arquivo:startLine.startCol,endLine.endCol numStmt count
-	parts := strings.Fields(line)
-	if len(parts) < 3 {
-		return nil, fmt.Errorf("formato inválido")
+// cutLastField separa o último campo de line, delimitado por espaços, e
+// retorna o restante sem os espaços finais. As colunas são relativas ao
+// início de line.
+func cutLastField(line string) (string, lineField, bool) {
+	i := strings.LastIndexFunc(line, unicode.IsSpace)
+	if i < 0 {
+		return "", lineField{line, 1}, false
 	}
+	field := lineField{line[i+1:], i + 2}
+	return strings.TrimRightFunc(line[:i], unicode.IsSpace), field, true
+}
+
+// parseCoverageLine parseia "arquivo:startLine.startCol,endLine.endCol
+// numStmt count". Como em golang.org/x/tools/cover, os campos são
+// localizados a partir do fim da linha, então o caminho do arquivo pode
+// conter ":" (ex.: C:/...) e espaços.
+func parseCoverageLine(line string) (*parsedCoverageLine, *ParseError) {
+	rest := strings.TrimRightFunc(line, unicode.IsSpace)
 
-	// Parse arquivo e posição
-	fileParts := strings.Split(parts[0], ":")
-	if len(fileParts) < 2 {
-		return nil, fmt.Errorf("formato de arquivo inválido")
+	rest, count, ok := cutLastField(rest)
+	if !ok {
+		return nil, newParseError(1, ErrInvalidFormat, "esperado arquivo:posição statements contador")
+	}
+	location, numStmt, ok := cutLastField(rest)
+	if !ok {
+		return nil, newParseError(1, ErrInvalidFormat, "esperado arquivo:posição statements contador")
 	}
 
-	filePath := fileParts[0]
-	posStr := fileParts[1]
+	// Parse arquivo e posição: a posição começa após o último ":"
+	colon := strings.LastIndex(location, ":")
+	if colon <= 0 {
+		return nil, newParseError(1, ErrInvalidFormat, "formato de arquivo inválido")
+	}
 
-	// Parse posição: startLine.startCol,endLine.endCol
-	posParts := strings.Split(posStr, ",")
-	if len(posParts) != 2 {
-		return nil, fmt.Errorf("formato de posição inválido")
+	parsed := &parsedCoverageLine{
+		FilePath:    location[:colon],
+		stmtColumn:  numStmt.column,
+		countColumn: count.column,
+	}
+	if err := parsed.parsePosition(location[colon+1:], colon+2); err != nil {
+		return nil, err
 	}
 
-	startParts := strings.Split(posParts[0], ".")
-	endParts := strings.Split(posParts[1], ".")
+	var err *ParseError
+	if parsed.Block.NumStmt, err = parseField(numStmt, "número de statements %q"); err != nil {
+		return nil, err
+	}
+	if parsed.Block.Count, err = parseField(count, "contador %q"); err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
 
-	if len(startParts) != 2 || len(endParts) != 2 {
-		return nil, fmt.Errorf("formato de coordenadas inválido")
+// parsePosition lê "startLine.startCol,endLine.endCol" a partir da coluna
+// informada
+func (p *parsedCoverageLine) parsePosition(pos string, column int) *ParseError {
+	start, end, ok := strings.Cut(pos, ",")
+	if !ok || strings.Contains(end, ",") {
+		return newParseError(column, ErrInvalidFormat, "formato de posição inválido %q", pos)
 	}
 
-	startLine, _ := strconv.Atoi(startParts[0])
-	startCol, _ := strconv.Atoi(startParts[1])
-	endLine, _ := strconv.Atoi(endParts[0])
-	endCol, _ := strconv.Atoi(endParts[1])
+	p.startColumn = column
+	p.endColumn = column + len(start) + 1
 
-	numStmt, _ := strconv.Atoi(parts[1])
-	count, _ := strconv.Atoi(parts[2])
+	var err *ParseError
+	if p.Block.StartLine, p.Block.StartCol, err = parseCoordinates(start, p.startColumn); err != nil {
+		return err
+	}
+	if p.Block.EndLine, p.Block.EndCol, err = parseCoordinates(end, p.endColumn); err != nil {
+		return err
+	}
+	return nil
+}
 
-	return &parsedCoverageLine{
-		FilePath: filePath,
-		Block: CoverageBlock{
-			StartLine: startLine,
-			StartCol:  startCol,
-			EndLine:   endLine,
-			EndCol:    endCol,
-			NumStmt:   numStmt,
-			Count:     count,
-		},
-	}, nil
+// parseCoordinates lê "linha.coluna"
+func parseCoordinates(text string, column int) (int, int, *ParseError) {
+	lineText, colText, ok := strings.Cut(text, ".")
+	if !ok {
+		return 0, 0, newParseError(column, ErrInvalidFormat, "formato de coordenadas inválido %q", text)
+	}
+
+	line, err := parseField(lineField{lineText, column}, "linha %q")
+	if err != nil {
+		return 0, 0, err
+	}
+	col, err := parseField(lineField{colText, column + len(lineText) + 1}, "coluna %q")
+	if err != nil {
+		return 0, 0, err
+	}
+	return line, col, nil
+}
+
+// parseField lê um campo numérico; format descreve o campo na mensagem de
+// erro (ex.: "contador %q")
+func parseField(field lineField, format string) (int, *ParseError) {
+	value, err := strconv.Atoi(field.text)
+	if err != nil {
+		return 0, newParseError(field.column, ErrInvalidNumber, format, field.text)
+	}
+	return value, nil
+}
+
+// validateBlock aplica as validações do modo estrito a um bloco
+func validateBlock(p *parsedCoverageLine, mode string) *ParseError {
+	b := p.Block
+
+	switch {
+	case b.StartLine < 1 || b.StartCol < 1:
+		return newParseError(p.startColumn, ErrInvalidRange, "início %d.%d fora do arquivo", b.StartLine, b.StartCol)
+	case b.EndLine < 1 || b.EndCol < 1:
+		return newParseError(p.endColumn, ErrInvalidRange, "fim %d.%d fora do arquivo", b.EndLine, b.EndCol)
+	case b.EndLine < b.StartLine || (b.EndLine == b.StartLine && b.EndCol < b.StartCol):
+		return newParseError(p.endColumn, ErrInvalidRange, "fim %d.%d antes do início %d.%d", b.EndLine, b.EndCol, b.StartLine, b.StartCol)
+	case b.NumStmt < 0:
+		return newParseError(p.stmtColumn, ErrInvalidNumber, "número de statements negativo: %d", b.NumStmt)
+	case b.Count < 0:
+		return newParseError(p.countColumn, ErrInvalidNumber, "contador negativo: %d", b.Count)
+	case mode == ModeSet && b.Count > 1:
+		return newParseError(p.countColumn, ErrInvalidNumber, "contador %d no modo set (esperado 0 ou 1)", b.Count)
+	}
+	return nil
 }
 
 // GetTotalCoverage calcula a cobertura total do projeto
@@ -168,11 +275,15 @@ func (pc *ProjectCoverage) GetTotalCoverage() float64 {
 	return float64(coveredStmt) / float64(totalStmt) * 100
 }
 
-// GetSortedFiles retorna arquivos ordenados por nome
+// GetSortedFiles retorna arquivos ordenados pelo caminho
 func (pc *ProjectCoverage) GetSortedFiles() []*FileCoverage {
 	files := make([]*FileCoverage, 0, len(pc.Files))
 	for _, file := range pc.Files {
 		files = append(files, file)
 	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].FilePath < files[j].FilePath
+	})
 	return files
 }
@@ -2,6 +2,9 @@ package coverage
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -109,7 +112,7 @@ pkg/file2.go:3.1,4.2 1 0
 		t.Error("HTML não contém nome do arquivo 2")
 	}
 
-	if !strings.Contains(html, "window.filesData") {
+	if !strings.Contains(html, `id="reportData"`) {
 		t.Error("HTML não contém dados dos arquivos")
 	}
 }
@@ -178,17 +181,251 @@ func TestParseCoverageLine(t *testing.T) {
 	}
 }
 
+func TestParseCoverageLinePaths(t *testing.T) {
+	want := CoverageBlock{StartLine: 10, StartCol: 5, EndLine: 15, EndCol: 10, NumStmt: 2, Count: 1}
+
+	tests := []struct {
+		name string
+		line string
+		path string
+	}{
+		{"caminho de módulo", "github.com/user/project/pkg/file.go:10.5,15.10 2 1", "github.com/user/project/pkg/file.go"},
+		{"Windows com barra", "C:/Users/dev/project/file.go:10.5,15.10 2 1", "C:/Users/dev/project/file.go"},
+		{"Windows com barra invertida", `C:\Users\dev\project\file.go:10.5,15.10 2 1`, `C:\Users\dev\project\file.go`},
+		{"dois-pontos no caminho", "example.com/a:b/c:d.go:10.5,15.10 2 1", "example.com/a:b/c:d.go"},
+		{"espaços no caminho", "my project/sub dir/file.go:10.5,15.10 2 1", "my project/sub dir/file.go"},
+		{"Unicode", "exemplo.com/configuração/açúcar_日本語.go:10.5,15.10 2 1", "exemplo.com/configuração/açúcar_日本語.go"},
+		{"emoji", "pkg/🚀/file.go:10.5,15.10 2 1", "pkg/🚀/file.go"},
+		{"pontos e vírgulas no caminho", "pkg/v1.2,3/file.test.go:10.5,15.10 2 1", "pkg/v1.2,3/file.test.go"},
+		{"final CRLF", "pkg/file.go:10.5,15.10 2 1\r", "pkg/file.go"},
+		{"vários espaços e tabs", "pkg/file.go:10.5,15.10 \t 2\t1  ", "pkg/file.go"},
+		{"caminho absoluto", "/home/dev/go/src/app/main.go:10.5,15.10 2 1", "/home/dev/go/src/app/main.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseCoverageLine(tt.line)
+			if err != nil {
+				t.Fatalf("erro ao parsear %q: %v", tt.line, err)
+			}
+			if parsed.FilePath != tt.path {
+				t.Errorf("arquivo = %q, esperado %q", parsed.FilePath, tt.path)
+			}
+			if parsed.Block != want {
+				t.Errorf("bloco = %+v, esperado %+v", parsed.Block, want)
+			}
+		})
+	}
+}
+
+func TestParseCoverageLineErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		cause  error
+		column int
+	}{
+		{"sem posição", "pkg/file.go 2 1", ErrInvalidFormat, 1},
+		{"caminho vazio", ":10.5,15.10 2 1", ErrInvalidFormat, 1},
+		{"sem contador", "pkg/file.go:10.5,15.10 2", ErrInvalidFormat, 1},
+		{"apenas um campo", "pkg/file.go:10.5,15.10", ErrInvalidFormat, 1},
+		{"posição com espaço no fim do caminho", "C:/a b.go:1.1,2.x 1 1", ErrInvalidNumber, 17},
+		{"posição incompleta", "C:/file.go:10.5 2 1", ErrInvalidFormat, 12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseCoverageLine(tt.line)
+			if err == nil {
+				t.Fatalf("esperado erro para %q", tt.line)
+			}
+			if !errors.Is(err, tt.cause) || err.Column != tt.column {
+				t.Errorf("erro = %v (coluna %d), esperado %v na coluna %d", err, err.Column, tt.cause, tt.column)
+			}
+		})
+	}
+}
+
+func TestParseCoverageFileUnusualPaths(t *testing.T) {
+	input := "mode: count\r\n" +
+		"C:/work/my app/main.go:3.14,5.2 1 4\r\n" +
+		"C:/work/my app/main.go:7.2,7.20 1 0\r\n" +
+		"C:/work/my app/ação.go:1.1,2.1 3 2\r\n"
+
+	coverage, _, err := ParseCoverageFileWithOptions(strings.NewReader(input), ParseOptions{Validation: ValidateStrict})
+	if err != nil {
+		t.Fatalf("erro ao parsear: %v", err)
+	}
+
+	if coverage.Mode != "count" {
+		t.Errorf("modo = %q, esperado 'count'", coverage.Mode)
+	}
+	main := coverage.Files["C:/work/my app/main.go"]
+	if main == nil || len(main.Blocks) != 2 || main.FileName != "main.go" {
+		t.Fatalf("arquivo com espaço no caminho inesperado: %+v", main)
+	}
+	if file := coverage.Files["C:/work/my app/ação.go"]; file == nil || file.CoveredStmt != 3 {
+		t.Errorf("arquivo Unicode inesperado: %+v", file)
+	}
+}
+
+func TestParseWithoutModeHeader(t *testing.T) {
+	input := `pkg/file.go:1.1,2.2 1 1
+pkg/file.go:3.1,4.2 1 0
+`
+
+	coverage, err := ParseCoverageFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("erro ao parsear: %v", err)
+	}
+
+	if coverage.Mode != "" {
+		t.Errorf("modo esperado vazio, obtido '%s'", coverage.Mode)
+	}
+	if n := len(coverage.Files["pkg/file.go"].Blocks); n != 2 {
+		t.Errorf("esperava 2 blocos (a primeira linha não pode ser perdida), obteve %d", n)
+	}
+}
+
+func TestParseRejectsInvalidNumbers(t *testing.T) {
+	input := "mode: set\npkg/file.go:1.1,2.x 1 1\n"
+
+	_, err := ParseCoverageFile(strings.NewReader(input))
+
+	var perr *ParseError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrInvalidNumber) {
+		t.Fatalf("esperado *ParseError com ErrInvalidNumber, obtido %v", err)
+	}
+	if perr.Line != 2 || perr.Column != 19 {
+		t.Errorf("posição do erro = %d:%d, esperado 2:19", perr.Line, perr.Column)
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		cause  error
+		line   int
+		column int
+	}{
+		{"sem cabeçalho", "pkg/a.go:1.1,2.1 1 1\n", ErrInvalidMode, 1, 1},
+		{"modo desconhecido", "mode: sometimes\n", ErrInvalidMode, 1, 7},
+		{"statements inválido", "mode: set\npkg/a.go:1.1,2.1 um 1\n", ErrInvalidNumber, 2, 18},
+		{"contador inválido", "mode: count\npkg/a.go:1.1,2.1 1 1e3\n", ErrInvalidNumber, 2, 20},
+		{"coluna inicial inválida", "mode: set\npkg/a.go:1.,2.1 1 1\n", ErrInvalidNumber, 2, 12},
+		{"sem vírgula", "mode: set\npkg/a.go:1.1 1 1\n", ErrInvalidFormat, 2, 10},
+		{"campo extra", "mode: set\npkg/a.go:1.1,2.1 1 1 9\n", ErrInvalidNumber, 2, 16},
+		{"faixa invertida", "mode: set\npkg/a.go:5.1,2.1 1 1\n", ErrInvalidRange, 2, 14},
+		{"mesma linha invertida", "mode: set\npkg/a.go:5.9,5.2 1 1\n", ErrInvalidRange, 2, 14},
+		{"linha zero", "mode: set\npkg/a.go:0.1,2.1 1 1\n", ErrInvalidRange, 2, 10},
+		{"statements negativo", "mode: set\npkg/a.go:1.1,2.1 -1 1\n", ErrInvalidNumber, 2, 18},
+		{"contador negativo", "mode: count\npkg/a.go:1.1,2.1 1 -4\n", ErrInvalidNumber, 2, 20},
+		{"contador no modo set", "mode: set\npkg/a.go:1.1,2.1 1 7\n", ErrInvalidNumber, 2, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseCoverageFileWithOptions(strings.NewReader(tt.input), ParseOptions{Validation: ValidateStrict})
+
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("esperado *ParseError, obtido %v", err)
+			}
+			if !errors.Is(err, tt.cause) {
+				t.Errorf("causa = %v, esperado %v", perr.Err, tt.cause)
+			}
+			if perr.Line != tt.line || perr.Column != tt.column {
+				t.Errorf("posição do erro = %d:%d, esperado %d:%d (%v)", perr.Line, perr.Column, tt.line, tt.column, err)
+			}
+		})
+	}
+
+	valid := "mode: count\npkg/a.go:1.1,2.1 1 7\npkg/a.go:2.1,2.5 0 0\n"
+	if _, _, err := ParseCoverageFileWithOptions(strings.NewReader(valid), ParseOptions{Validation: ValidateStrict}); err != nil {
+		t.Errorf("perfil válido rejeitado: %v", err)
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	input := `mode: set
+pkg/a.go:1.1,2.1 1 1
+pkg/a.go:3.1,4.x 1 1
+lixo
+pkg/a.go:5.1,6.1 1 0
+pkg/a.go:1.1,2.1 2 1
+`
+
+	coverage, warnings, err := ParseCoverageFileWithOptions(strings.NewReader(input), ParseOptions{Validation: ValidateLenient})
+	if err != nil {
+		t.Fatalf("o modo tolerante não deveria abortar: %v", err)
+	}
+
+	if n := len(coverage.Files["pkg/a.go"].Blocks); n != 2 {
+		t.Errorf("esperava 2 blocos válidos, obteve %d", n)
+	}
+
+	var lines []int
+	for _, w := range warnings {
+		lines = append(lines, w.Line)
+	}
+	if !slices.Equal(lines, []int{3, 4, 6}) {
+		t.Errorf("avisos nas linhas %v, esperado [3 4 6]", lines)
+	}
+}
+
 func BenchmarkParseCoverageFile(b *testing.B) {
-	input := `mode: atomic
+	small := `mode: atomic
 pkg/file1.go:1.1,2.2 1 1
 pkg/file2.go:3.1,4.2 1 0
 pkg/file3.go:5.1,6.2 1 1
 pkg/file4.go:7.1,8.2 1 0
 pkg/file5.go:9.1,10.2 1 1
 `
+	large := benchmarkProfile(200, 500)
+
+	for _, input := range []struct {
+		name    string
+		profile string
+	}{{"pequeno", small}, {"grande", large}} {
+		b.Run(input.name+"/ParseCoverageFile", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input.profile)))
+			for i := 0; i < b.N; i++ {
+				reader := strings.NewReader(input.profile)
+				ParseCoverageFile(reader)
+			}
+		})
+
+		b.Run(input.name+"/ProfileScanner", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input.profile)))
+			for i := 0; i < b.N; i++ {
+				scanner := NewProfileScanner(strings.NewReader(input.profile), ParseOptions{})
+				for scanner.Scan() {
+				}
+			}
+		})
+
+		b.Run(input.name+"/AggregateProfile", func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input.profile)))
+			for i := 0; i < b.N; i++ {
+				AggregateProfile(strings.NewReader(input.profile), ParseOptions{})
+			}
+		})
+	}
+}
 
-	for i := 0; i < b.N; i++ {
-		reader := strings.NewReader(input)
-		ParseCoverageFile(reader)
+// benchmarkProfile gera um perfil no modo count com o número de arquivos e
+// de blocos por arquivo informados
+func benchmarkProfile(files, blocks int) string {
+	var sb strings.Builder
+	sb.WriteString("mode: count\n")
+	for f := 0; f < files; f++ {
+		for i := 0; i < blocks; i++ {
+			fmt.Fprintf(&sb, "github.com/user/project/internal/pkg%03d/file.go:%d.2,%d.40 %d %d\n", f, i*3+1, i*3+2, i%5+1, i%7)
+		}
 	}
+	return sb.String()
 }
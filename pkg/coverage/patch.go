@@ -0,0 +1,329 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangedLines mapeia o caminho de cada arquivo de um diff para as linhas
+// adicionadas ou modificadas (numeração do arquivo novo), em ordem crescente
+type ChangedLines map[string][]int
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ParseUnifiedDiff lê um diff unificado (como o gerado por git diff) e
+// retorna as linhas alteradas de cada arquivo. Arquivos removidos são
+// ignorados e os prefixos a/ e b/ do git são descartados.
+func ParseUnifiedDiff(reader io.Reader) (ChangedLines, error) {
+	return ParseUnifiedDiffWithOptions(reader, ParseOptions{})
+}
+
+// ParseUnifiedDiffWithOptions é ParseUnifiedDiff com os erros no idioma de
+// opts.Language. Hunks inválidos geram um *ParseError com a linha.
+func ParseUnifiedDiffWithOptions(reader io.Reader, opts ParseOptions) (ChangedLines, error) {
+	lang := opts.Language
+	if err := lang.Validate(); err != nil {
+		return nil, err
+	}
+	changes := make(ChangedLines)
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	var (
+		header           string // caminho da última linha +++
+		current          string // arquivo do hunk atual ("" se removido)
+		newLine          int    // próxima linha do arquivo novo
+		oldLeft, newLeft int    // linhas restantes no hunk atual
+		lineNum          int
+		inHunk           bool
+	)
+
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		if inHunk && (oldLeft > 0 || newLeft > 0) {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				if current != "" {
+					changes[current] = append(changes[current], newLine)
+				}
+				newLine++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				// linha de contexto (" " ou vazia)
+				newLine++
+				newLeft--
+				oldLeft--
+			}
+			continue
+		}
+		inHunk = false
+
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			header = diffPath(line[4:], "b/")
+			current = header
+			if current == "/dev/null" {
+				current = ""
+			}
+		case strings.HasPrefix(line, "@@"):
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				return nil, &ParseError{Line: lineNum, Err: newLocalizedError(ErrInvalidFormat, "cabeçalho de hunk %q", line), lang: lang}
+			}
+			if header == "" {
+				return nil, &ParseError{Line: lineNum, Err: newLocalizedError(ErrInvalidFormat, "hunk sem cabeçalho de arquivo"), lang: lang}
+			}
+			newLine, _ = strconv.Atoi(m[3])
+			oldLeft = hunkLength(m[2])
+			newLeft = hunkLength(m[4])
+			inHunk = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf(lang.text("erro ao ler diff: %w"), err)
+	}
+
+	for path, lines := range changes {
+		sort.Ints(lines)
+		changes[path] = lines
+	}
+	return changes, nil
+}
+
+// hunkLength interpreta o tamanho opcional de um hunk (padrão 1)
+func hunkLength(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// diffPath extrai o caminho de uma linha ---/+++ removendo o prefixo do git,
+// aspas e o timestamp do formato diff -u
+func diffPath(raw, prefix string) string {
+	if i := strings.IndexByte(raw, '\t'); i >= 0 {
+		raw = raw[:i]
+	}
+	raw = strings.TrimSpace(raw)
+	if unquoted, err := strconv.Unquote(raw); err == nil {
+		raw = unquoted
+	}
+	if raw == "/dev/null" {
+		return raw
+	}
+	return strings.TrimPrefix(raw, prefix)
+}
+
+// FilePatchCoverage é a cobertura das linhas alteradas de um arquivo
+type FilePatchCoverage struct {
+	FilePath       string // caminho no perfil de cobertura
+	DiffPath       string // caminho no diff
+	ChangedLines   []int  // todas as linhas alteradas
+	CoveredLines   []int  // linhas alteradas dentro de blocos cobertos
+	UncoveredLines []int  // linhas alteradas dentro de blocos não cobertos
+	TotalStmt      int    // statements dos blocos que tocam linhas alteradas
+	CoveredStmt    int
+	Coverage       float64 // percentual de 0-100
+}
+
+// PatchCoverage é a cobertura considerando apenas as linhas alteradas
+type PatchCoverage struct {
+	Files       []FilePatchCoverage // ordenados pelo caminho no perfil
+	TotalStmt   int
+	CoveredStmt int
+	Coverage    float64 // percentual de 0-100
+}
+
+// ComputePatchCoverage cruza as linhas alteradas com os blocos do perfil.
+// Um bloco conta quando alguma das suas linhas foi alterada. Os caminhos do
+// diff (relativos ao repositório) são associados aos do perfil (caminhos de
+// import) pelo maior sufixo em comum; arquivos sem statements alterados, como
+// testes e arquivos que não são Go, não entram no resultado.
+func ComputePatchCoverage(coverage *ProjectCoverage, changes ChangedLines) *PatchCoverage {
+	patch := &PatchCoverage{Files: []FilePatchCoverage{}}
+
+	for diffPath, lines := range changes {
+		file := matchProfileFile(coverage, diffPath)
+		if file == nil {
+			continue
+		}
+
+		fpc := filePatchCoverage(file, lines)
+		if fpc.TotalStmt == 0 {
+			continue
+		}
+		fpc.DiffPath = diffPath
+
+		patch.Files = append(patch.Files, fpc)
+		patch.TotalStmt += fpc.TotalStmt
+		patch.CoveredStmt += fpc.CoveredStmt
+	}
+
+	sort.Slice(patch.Files, func(i, j int) bool {
+		return patch.Files[i].FilePath < patch.Files[j].FilePath
+	})
+
+	if patch.TotalStmt > 0 {
+		patch.Coverage = float64(patch.CoveredStmt) / float64(patch.TotalStmt) * 100
+	}
+	return patch
+}
+
+// matchProfileFile encontra o arquivo do perfil correspondente a um caminho
+// do diff, preferindo o caminho idêntico e depois o maior sufixo em comum
+func matchProfileFile(coverage *ProjectCoverage, diffPath string) *FileCoverage {
+	if file, ok := coverage.Files[diffPath]; ok {
+		return file
+	}
+
+	var best *FileCoverage
+	bestLen := 0
+	for filePath, file := range coverage.Files {
+		matched := 0
+		switch {
+		case strings.HasSuffix(filePath, "/"+diffPath):
+			matched = len(diffPath)
+		case strings.HasSuffix(diffPath, "/"+filePath):
+			matched = len(filePath)
+		}
+		if matched == 0 || matched < bestLen {
+			continue
+		}
+		// Em caso de empate, o caminho mais curto é o mais próximo da raiz
+		if matched > bestLen || len(filePath) < len(best.FilePath) ||
+			(len(filePath) == len(best.FilePath) && filePath < best.FilePath) {
+			best, bestLen = file, matched
+		}
+	}
+	return best
+}
+
+func filePatchCoverage(file *FileCoverage, lines []int) FilePatchCoverage {
+	fpc := FilePatchCoverage{
+		FilePath:     file.FilePath,
+		ChangedLines: lines,
+	}
+
+	changed := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		changed[line] = true
+	}
+
+	lineState := make(map[int]int)
+	for _, block := range file.Blocks {
+		touched := false
+		for line := block.StartLine; line <= block.EndLine; line++ {
+			if !changed[line] {
+				continue
+			}
+			touched = true
+			if block.Count > 0 {
+				lineState[line] = segmentCovered
+			} else if _, ok := lineState[line]; !ok {
+				lineState[line] = segmentUncovered
+			}
+		}
+
+		if touched {
+			fpc.TotalStmt += block.NumStmt
+			if block.Count > 0 {
+				fpc.CoveredStmt += block.NumStmt
+			}
+		}
+	}
+
+	for _, line := range lines {
+		state, ok := lineState[line]
+		switch {
+		case !ok:
+		case state == segmentCovered:
+			fpc.CoveredLines = append(fpc.CoveredLines, line)
+		default:
+			fpc.UncoveredLines = append(fpc.UncoveredLines, line)
+		}
+	}
+
+	if fpc.TotalStmt > 0 {
+		fpc.Coverage = float64(fpc.CoveredStmt) / float64(fpc.TotalStmt) * 100
+	}
+	return fpc
+}
+
+// FilePatch retorna a cobertura do patch de um arquivo do perfil
+func (pc *PatchCoverage) FilePatch(filePath string) (FilePatchCoverage, bool) {
+	i := sort.Search(len(pc.Files), func(i int) bool {
+		return pc.Files[i].FilePath >= filePath
+	})
+	if i < len(pc.Files) && pc.Files[i].FilePath == filePath {
+		return pc.Files[i], true
+	}
+	return FilePatchCoverage{}, false
+}
+
+// CheckThreshold retorna uma violação se a cobertura do patch ficar abaixo
+// do mínimo. Patches sem statements alterados não geram violação.
+func (pc *PatchCoverage) CheckThreshold(min float64) []ThresholdViolation {
+	if min <= 0 || pc.TotalStmt == 0 || pc.Coverage >= min {
+		return nil
+	}
+	return []ThresholdViolation{{Scope: ScopePatch, Min: min, Actual: pc.Coverage}}
+}
+
+// WriteSummary escreve um resumo em texto da cobertura do patch em
+// DefaultLanguage
+func (pc *PatchCoverage) WriteSummary(w io.Writer) error {
+	return pc.WriteLocalizedSummary(w, DefaultLanguage)
+}
+
+// WriteLocalizedSummary escreve o resumo de WriteSummary no idioma informado
+func (pc *PatchCoverage) WriteLocalizedSummary(w io.Writer, lang Language) error {
+	if err := lang.Validate(); err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(lang.tr("Cobertura do patch: %s (%s/%s statements)", lang.percent(pc.Coverage), lang.number(pc.CoveredStmt), lang.number(pc.TotalStmt)))
+	sb.WriteString("\n")
+	for _, file := range pc.Files {
+		fmt.Fprintf(&sb, "  %s: %s (%s/%s)", file.FilePath, lang.percent(file.Coverage), lang.number(file.CoveredStmt), lang.number(file.TotalStmt))
+		if len(file.UncoveredLines) > 0 {
+			sb.WriteString(lang.tr(" — linhas sem cobertura: %s", formatLineRanges(file.UncoveredLines)))
+		}
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// formatLineRanges agrupa linhas consecutivas: [1 2 3 7] -> "1-3, 7"
+func formatLineRanges(lines []int) string {
+	var parts []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(lines[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,183 @@
+package coverage
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const samplePatch = `diff --git a/pkg/calc/sum.go b/pkg/calc/sum.go
+index 1111111..2222222 100644
+--- a/pkg/calc/sum.go
++++ b/pkg/calc/sum.go
+@@ -3,3 +3,7 @@ package calc
+ func Sum(a, b int) int {
+-	return a + b
++	if a < 0 {
++		return b
++	}
++	return a + b
+ }
++
+@@ -20 +23,2 @@ func Other() {
+-	old()
++	x()
++	y()
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+--- título antigo
++++ título novo
+diff --git a/pkg/calc/gone.go b/pkg/calc/gone.go
+deleted file mode 100644
+--- a/pkg/calc/gone.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package calc
+-
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	changes, err := ParseUnifiedDiff(strings.NewReader(samplePatch))
+	if err != nil {
+		t.Fatalf("erro ao parsear diff: %v", err)
+	}
+
+	want := ChangedLines{
+		"pkg/calc/sum.go": {4, 5, 6, 7, 9, 23, 24},
+		"README.md":       {1},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("linhas alteradas = %v, esperado %v", changes, want)
+	}
+}
+
+func TestParseUnifiedDiffErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"hunk inválido", "+++ b/a.go\n@@ -1 +x @@\n"},
+		{"hunk sem arquivo", "@@ -1 +1 @@\n+a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseUnifiedDiff(strings.NewReader(tt.input)); err == nil {
+				t.Error("esperava erro")
+			}
+		})
+	}
+}
+
+func TestComputePatchCoverage(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 1
+example.com/demo/pkg/calc/sum.go:4.11,6.3 1 0
+example.com/demo/pkg/calc/sum.go:7.2,7.14 1 1
+example.com/demo/pkg/calc/sum.go:23.2,24.5 2 0
+example.com/demo/pkg/other/x.go:1.1,2.2 5 0
+`)
+
+	changes := ChangedLines{
+		"pkg/calc/sum.go":     {4, 5, 6, 7, 9, 23, 24},
+		"README.md":           {1},
+		"pkg/other/x_test.go": {1},
+	}
+
+	patch := ComputePatchCoverage(cov, changes)
+	if len(patch.Files) != 1 {
+		t.Fatalf("esperava 1 arquivo, obteve %d: %+v", len(patch.Files), patch.Files)
+	}
+
+	file := patch.Files[0]
+	if file.FilePath != "example.com/demo/pkg/calc/sum.go" || file.DiffPath != "pkg/calc/sum.go" {
+		t.Errorf("caminhos = %s / %s", file.FilePath, file.DiffPath)
+	}
+	if file.TotalStmt != 5 || file.CoveredStmt != 2 {
+		t.Errorf("statements = %d/%d, esperado 2/5", file.CoveredStmt, file.TotalStmt)
+	}
+	if !reflect.DeepEqual(file.CoveredLines, []int{4, 7}) {
+		t.Errorf("linhas cobertas = %v", file.CoveredLines)
+	}
+	if !reflect.DeepEqual(file.UncoveredLines, []int{5, 6, 23, 24}) {
+		t.Errorf("linhas sem cobertura = %v", file.UncoveredLines)
+	}
+	if patch.Coverage != 40 {
+		t.Errorf("cobertura do patch = %.1f, esperado 40", patch.Coverage)
+	}
+
+	if v := patch.CheckThreshold(50); len(v) != 1 || v[0].Scope != ScopePatch {
+		t.Errorf("violações = %v", v)
+	}
+	if v := patch.CheckThreshold(40); len(v) != 0 {
+		t.Errorf("não esperava violações, obteve %v", v)
+	}
+
+	var buf bytes.Buffer
+	if err := patch.WriteSummary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "linhas sem cobertura: 5-6, 23-24") {
+		t.Errorf("resumo inesperado:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := patch.WriteLocalizedSummary(&buf, LanguageEnglish); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "Patch coverage: ") || !strings.Contains(buf.String(), "uncovered lines: 5-6, 23-24") {
+		t.Errorf("resumo em inglês inesperado:\n%s", buf.String())
+	}
+}
+
+func TestMatchProfileFile(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/svc/pkg/a.go:1.1,2.2 1 1
+example.com/svc/internal/pkg/a.go:1.1,2.2 1 1
+pkg/b.go:1.1,2.2 1 1
+`)
+
+	tests := []struct {
+		diffPath string
+		want     string
+	}{
+		{"pkg/a.go", "example.com/svc/pkg/a.go"},
+		{"svc/internal/pkg/a.go", "example.com/svc/internal/pkg/a.go"},
+		{"pkg/b.go", "pkg/b.go"},
+		{"repo/pkg/b.go", "pkg/b.go"},
+		{"a.go.orig", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.diffPath, func(t *testing.T) {
+			got := ""
+			if file := matchProfileFile(cov, tt.diffPath); file != nil {
+				got = file.FilePath
+			}
+			if got != tt.want {
+				t.Errorf("matchProfileFile(%q) = %q, esperado %q", tt.diffPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLGenerationWithPatch(t *testing.T) {
+	cov := mustParse(t, "mode: set\npkg/calc/sum.go:3.24,4.11 1 1\n")
+	patch := ComputePatchCoverage(cov, ChangedLines{"pkg/calc/sum.go": {3, 4}})
+
+	var buf bytes.Buffer
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{Patch: patch}).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	html := buf.String()
+	if !strings.Contains(html, "Cobertura do Patch") {
+		t.Error("HTML não contém a cobertura do patch")
+	}
+	if !strings.Contains(html, `"changed":[3,4]`) {
+		t.Error("HTML não contém as linhas alteradas")
+	}
+}
@@ -0,0 +1,241 @@
+package coverage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ProfileScanner lê um perfil de cobertura do Go bloco a bloco, como um
+// bufio.Scanner. Apenas a linha atual fica em memória, sem limite de
+// tamanho de linha, o que permite processar perfis muito grandes sem
+// montar um ProjectCoverage.
+//
+//	scanner := coverage.NewProfileScanner(file, coverage.ParseOptions{})
+//	for scanner.Scan() {
+//		fmt.Println(scanner.FilePath(), scanner.Block().Count)
+//	}
+//	if err := scanner.Err(); err != nil { ... }
+//
+// Blocos repetidos na mesma posição são entregues como aparecem no perfil.
+type ProfileScanner struct {
+	reader *bufio.Reader
+	opts   ParseOptions
+	strict bool
+
+	mode     string
+	started  bool
+	line     []byte // buffer reaproveitado entre as linhas
+	lineNum  int
+	lastPath string // reaproveita a string do caminho entre blocos do mesmo arquivo
+	current  *parsedCoverageLine
+	warnings []*ParseError
+	err      error
+}
+
+// NewProfileScanner cria um scanner com o nível de validação informado
+func NewProfileScanner(reader io.Reader, opts ParseOptions) *ProfileScanner {
+	return &ProfileScanner{
+		reader: bufio.NewReader(reader),
+		opts:   opts,
+		strict: opts.Validation != ValidateDefault,
+	}
+}
+
+// Scan avança para o próximo bloco. Retorna false no fim do perfil ou no
+// primeiro erro; use Err para distinguir os casos.
+func (s *ProfileScanner) Scan() bool {
+	s.current = nil
+	if s.err != nil {
+		return false
+	}
+
+	for {
+		line, ok := s.readLine()
+		if !ok {
+			if s.err == nil && !s.started {
+				s.err = errors.New(s.opts.Language.text("arquivo de cobertura vazio"))
+			}
+			return false
+		}
+
+		if !s.started {
+			s.started = true
+			mode, perr := parseModeLine(line, s.strict)
+			if perr != nil && !s.report(perr) {
+				return false
+			}
+			s.mode = mode
+
+			// Sem cabeçalho, a primeira linha já é um bloco
+			if strings.HasPrefix(line, "mode:") {
+				continue
+			}
+		}
+
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		parsed, perr := parseCoverageLine(line)
+		if perr == nil && s.strict {
+			perr = validateBlock(parsed, s.mode)
+		}
+		if perr != nil {
+			if !s.report(perr) {
+				return false
+			}
+			continue
+		}
+
+		if parsed.FilePath == s.lastPath {
+			parsed.FilePath = s.lastPath
+		} else {
+			// Copia o caminho para não manter a linha inteira em memória
+			parsed.FilePath = strings.Clone(parsed.FilePath)
+			s.lastPath = parsed.FilePath
+		}
+		s.current = parsed
+		return true
+	}
+}
+
+// readLine lê a próxima linha sem o terminador, sem o limite de 64KB do
+// bufio.Scanner
+func (s *ProfileScanner) readLine() (string, bool) {
+	s.line = s.line[:0]
+	for {
+		chunk, isPrefix, err := s.reader.ReadLine()
+		if err != nil {
+			if err != io.EOF {
+				s.err = fmt.Errorf(s.opts.Language.text("erro ao ler arquivo: %w"), err)
+			}
+			return "", false
+		}
+		s.line = append(s.line, chunk...)
+		if !isPrefix {
+			break
+		}
+	}
+	s.lineNum++
+	return string(s.line), true
+}
+
+// report registra o erro da linha atual e indica se a leitura pode
+// continuar (modo ValidateLenient)
+func (s *ProfileScanner) report(err *ParseError) bool {
+	err.Line = s.lineNum
+	err.lang = s.opts.Language
+	if s.opts.Validation == ValidateLenient {
+		s.warnings = append(s.warnings, err)
+		return true
+	}
+	s.err = err
+	return false
+}
+
+// Mode retorna o modo do cabeçalho; vazio antes do primeiro Scan ou em
+// perfis sem cabeçalho
+func (s *ProfileScanner) Mode() string {
+	return s.mode
+}
+
+// FilePath retorna o arquivo do bloco atual
+func (s *ProfileScanner) FilePath() string {
+	if s.current == nil {
+		return ""
+	}
+	return s.current.FilePath
+}
+
+// Block retorna o bloco atual
+func (s *ProfileScanner) Block() CoverageBlock {
+	if s.current == nil {
+		return CoverageBlock{}
+	}
+	return s.current.Block
+}
+
+// Line retorna o número da linha do bloco atual
+func (s *ProfileScanner) Line() int {
+	return s.lineNum
+}
+
+// Warnings retorna as linhas ignoradas no modo ValidateLenient
+func (s *ProfileScanner) Warnings() []*ParseError {
+	return s.warnings
+}
+
+// Err retorna o erro que interrompeu a leitura, se houver
+func (s *ProfileScanner) Err() error {
+	return s.err
+}
+
+// aggregateKey é a posição de um bloco em formato compacto
+type aggregateKey struct {
+	StartLine, StartCol, EndLine, EndCol int32
+}
+
+// aggregateBlock é o mínimo necessário para unificar blocos repetidos
+type aggregateBlock struct {
+	NumStmt int32
+	Covered bool
+}
+
+// AggregateProfile lê o perfil com um ProfileScanner mantendo apenas os
+// totais de cada arquivo: os FileCoverage retornados não têm Blocks. Para
+// unificar blocos repetidos (como ParseCoverageFile) cada posição distinta
+// ocupa alguns bytes durante a leitura, independentemente dos contadores e
+// do tamanho do perfil.
+func AggregateProfile(reader io.Reader, opts ParseOptions) (*ProjectCoverage, []*ParseError, error) {
+	scanner := NewProfileScanner(reader, opts)
+	coverage := &ProjectCoverage{Files: make(map[string]*FileCoverage)}
+	seen := make(map[string]map[aggregateKey]aggregateBlock)
+
+	for scanner.Scan() {
+		filePath, block := scanner.FilePath(), scanner.Block()
+
+		file, ok := coverage.Files[filePath]
+		if !ok {
+			file = &FileCoverage{FilePath: filePath, FileName: filepath.Base(filePath)}
+			coverage.Files[filePath] = file
+			seen[filePath] = make(map[aggregateKey]aggregateBlock)
+		}
+
+		key := aggregateKey{int32(block.StartLine), int32(block.StartCol), int32(block.EndLine), int32(block.EndCol)}
+		covered := block.Count > 0
+
+		existing, repeated := seen[filePath][key]
+		switch {
+		case !repeated:
+			file.TotalStmt += block.NumStmt
+			if covered {
+				file.CoveredStmt += block.NumStmt
+			}
+			seen[filePath][key] = aggregateBlock{NumStmt: int32(block.NumStmt), Covered: covered}
+		case int(existing.NumStmt) != block.NumStmt:
+			err := newLocalizedError(nil, "%s:%d.%d,%d.%d: número de statements divergente (%d e %d)",
+				filePath, key.StartLine, key.StartCol, key.EndLine, key.EndCol, existing.NumStmt, block.NumStmt)
+			if !scanner.report(&ParseError{Column: scanner.current.stmtColumn, Err: err}) {
+				return nil, nil, scanner.Err()
+			}
+		case covered && !existing.Covered:
+			file.CoveredStmt += block.NumStmt
+			seen[filePath][key] = aggregateBlock{NumStmt: existing.NumStmt, Covered: true}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	coverage.Mode = scanner.Mode()
+	for _, file := range coverage.Files {
+		if file.TotalStmt > 0 {
+			file.Coverage = float64(file.CoveredStmt) / float64(file.TotalStmt) * 100
+		}
+	}
+	return coverage, scanner.Warnings(), nil
+}
@@ -0,0 +1,137 @@
+package coverage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestProfileScanner(t *testing.T) {
+	input := `mode: count
+pkg/a.go:1.1,2.1 2 5
+pkg/a.go:3.1,4.1 1 0
+
+pkg/b.go:1.1,2.1 3 1
+pkg/a.go:1.1,2.1 2 1
+`
+
+	scanner := NewProfileScanner(strings.NewReader(input), ParseOptions{})
+
+	var got []string
+	for scanner.Scan() {
+		b := scanner.Block()
+		got = append(got, fmt.Sprintf("%d %s:%d.%d,%d.%d %d %d",
+			scanner.Line(), scanner.FilePath(), b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.NumStmt, b.Count))
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	want := []string{
+		"2 pkg/a.go:1.1,2.1 2 5",
+		"3 pkg/a.go:3.1,4.1 1 0",
+		"5 pkg/b.go:1.1,2.1 3 1",
+		"6 pkg/a.go:1.1,2.1 2 1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("blocos:\n%s\nesperado:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if scanner.Mode() != "count" {
+		t.Errorf("modo = %q, esperado 'count'", scanner.Mode())
+	}
+	if scanner.Scan() {
+		t.Error("Scan após o fim deveria retornar false")
+	}
+}
+
+func TestProfileScannerErrors(t *testing.T) {
+	scanner := NewProfileScanner(strings.NewReader(""), ParseOptions{})
+	if scanner.Scan() || scanner.Err() == nil {
+		t.Error("esperado erro para perfil vazio")
+	}
+
+	scanner = NewProfileScanner(strings.NewReader("mode: set\npkg/a.go:1.1,2.1 1 1\nlixo\npkg/a.go:3.1,4.1 1 1\n"), ParseOptions{})
+	blocks := 0
+	for scanner.Scan() {
+		blocks++
+	}
+	if blocks != 1 || scanner.Err() == nil {
+		t.Errorf("esperado 1 bloco e erro na linha 3, obtido %d blocos e %v", blocks, scanner.Err())
+	}
+}
+
+func TestParseLongLines(t *testing.T) {
+	// Caminhos maiores que o limite de 64KB do bufio.Scanner
+	longPath := strings.Repeat("d", 100*1024) + "/file.go"
+	input := "mode: set\n" + longPath + ":1.1,2.1 1 1\npkg/short.go:1.1,2.1 1 0\n"
+
+	coverage, err := ParseCoverageFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("erro ao parsear linha longa: %v", err)
+	}
+	if coverage.Files[longPath] == nil || coverage.Files["pkg/short.go"] == nil {
+		t.Errorf("arquivos esperados não encontrados: %d arquivos", len(coverage.Files))
+	}
+}
+
+func TestAggregateProfile(t *testing.T) {
+	for _, mode := range []string{ModeSet, ModeCount} {
+		t.Run(mode, func(t *testing.T) {
+			// Blocos repetidos, como os gerados com -coverpkg
+			input := "mode: " + mode + `
+pkg/a.go:1.1,2.1 2 0
+pkg/a.go:3.1,4.1 1 1
+pkg/b.go:1.1,2.1 4 0
+pkg/a.go:1.1,2.1 2 1
+pkg/a.go:3.1,4.1 1 0
+pkg/b.go:1.1,2.1 4 0
+pkg/c.go:1.1,1.5 0 1
+`
+			full, err := ParseCoverageFile(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			aggregated, _, err := AggregateProfile(strings.NewReader(input), ParseOptions{})
+			if err != nil {
+				t.Fatalf("erro ao agregar: %v", err)
+			}
+
+			if aggregated.Mode != full.Mode || len(aggregated.Files) != len(full.Files) {
+				t.Fatalf("agregado difere do parse completo: %+v", aggregated)
+			}
+			for path, want := range full.Files {
+				got := aggregated.Files[path]
+				if got.TotalStmt != want.TotalStmt || got.CoveredStmt != want.CoveredStmt || got.Coverage != want.Coverage {
+					t.Errorf("%s: totais %d/%d (%.1f%%), esperado %d/%d (%.1f%%)", path,
+						got.CoveredStmt, got.TotalStmt, got.Coverage, want.CoveredStmt, want.TotalStmt, want.Coverage)
+				}
+				if got.Blocks != nil {
+					t.Errorf("%s: o modo agregado não deveria manter blocos", path)
+				}
+			}
+			if aggregated.GetTotalCoverage() != full.GetTotalCoverage() {
+				t.Errorf("cobertura total %.2f, esperado %.2f", aggregated.GetTotalCoverage(), full.GetTotalCoverage())
+			}
+		})
+	}
+}
+
+func TestAggregateProfileStatementMismatch(t *testing.T) {
+	input := "mode: set\npkg/a.go:1.1,2.1 2 0\npkg/a.go:1.1,2.1 3 1\npkg/a.go:3.1,4.1 1 1\n"
+
+	if _, _, err := AggregateProfile(strings.NewReader(input), ParseOptions{}); err == nil {
+		t.Error("esperado erro para número de statements divergente")
+	}
+
+	coverage, warnings, err := AggregateProfile(strings.NewReader(input), ParseOptions{Validation: ValidateLenient})
+	if err != nil {
+		t.Fatalf("o modo tolerante não deveria abortar: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Line != 3 {
+		t.Errorf("avisos inesperados: %v", warnings)
+	}
+	if file := coverage.Files["pkg/a.go"]; file.TotalStmt != 3 || file.CoveredStmt != 1 {
+		t.Errorf("totais inesperados: %d/%d", file.CoveredStmt, file.TotalStmt)
+	}
+}
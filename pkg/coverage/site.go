@@ -0,0 +1,393 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// Estrutura do diretório gerado pelo SiteGenerator
+const (
+	siteIndexPage   = "index.html"
+	siteStylesheet  = "assets/style.css"
+	siteScript      = "assets/site.js"
+	sitePackagesDir = "packages"
+	siteFilesDir    = "files"
+)
+
+// SiteGenerator gera o relatório como um site estático: uma página inicial,
+// uma página por pacote e uma por arquivo. CSS e JavaScript são escritos uma
+// única vez em assets/ e as páginas usam apenas links relativos, então o
+// diretório pode ser servido de qualquer host estático ou artefato de CI.
+//
+// As páginas são renderizadas pelos blocos site* dos templates do
+// HTMLGenerator, então HTMLOptions.Templates e HTMLOptions.Blocks também
+// valem para o site.
+type SiteGenerator struct {
+	coverage *ProjectCoverage
+	opts     HTMLOptions
+	html     *HTMLGenerator // templates e modelo de dados compartilhados com o HTML único
+}
+
+// Tipos de página do site (SitePage.Kind)
+const (
+	SitePageIndex   = "index"
+	SitePagePackage = "package"
+	SitePageFile    = "file"
+)
+
+// SitePage é o modelo de dados passado ao bloco "site" de cada página do
+// SiteGenerator. As URLs são relativas à página.
+type SitePage struct {
+	Kind       string       // SitePageIndex, SitePagePackage ou SitePageFile
+	Title      string       // título da página
+	Language   Language     // idioma do relatório, usado no atributo lang
+	Coverage   HTMLCoverage // cobertura do projeto, pacote ou arquivo da página
+	Crumbs     []SiteLink   // navegação no topo; vazio na página inicial
+	Stylesheet string       // URL de assets/style.css
+	Script     string       // URL de assets/site.js
+
+	Report   *HTMLReport // página inicial: totais para o bloco stats
+	Packages []SiteEntry // página inicial: tabela de pacotes
+	Files    []SiteEntry // página de pacote: tabela de arquivos
+	File     *SiteFile   // página de arquivo: código e funções
+}
+
+// SiteLink é um item da navegação; URL é vazia para a página atual
+type SiteLink struct {
+	Name string
+	URL  string
+}
+
+// SiteEntry é uma linha das tabelas de pacotes e de arquivos do site
+type SiteEntry struct {
+	Name     string
+	URL      string
+	Files    int // arquivos do pacote; zero nas linhas de arquivos
+	Coverage HTMLCoverage
+	Delta    *HTMLDelta // variação em relação à base; nil sem HTMLOptions.Diff
+}
+
+// SiteFile é o conteúdo da página de um arquivo
+type SiteFile struct {
+	Name          string
+	Path          string
+	SourceMissing bool       // código indisponível: Lines tem só as linhas instrumentadas
+	Lines         []SiteLine // linhas do código
+	Functions     []HTMLFunction
+}
+
+// SiteLine é uma linha do código de um arquivo
+type SiteLine struct {
+	Number   int
+	Class    string // covered, uncovered e/ou changed
+	Segments []SiteSegment
+}
+
+// SiteSegment é um trecho de uma linha; Class é segment-covered,
+// segment-uncovered ou vazio para trechos fora dos blocos
+type SiteSegment struct {
+	Text  string
+	Class string
+}
+
+// NewSiteGenerator cria um gerador de site com as mesmas opções do
+// HTMLGenerator
+func NewSiteGenerator(coverage *ProjectCoverage, opts HTMLOptions) *SiteGenerator {
+	return &SiteGenerator{
+		coverage: coverage,
+		opts:     opts,
+		html:     NewHTMLGeneratorWithOptions(coverage, opts),
+	}
+}
+
+// Generate escreve o site no diretório informado, criando-o se necessário.
+// Páginas de gerações anteriores que não existem mais não são removidas.
+func (sg *SiteGenerator) Generate(dir string) error {
+	if err := sg.opts.Buckets.Validate(); err != nil {
+		return err
+	}
+	if err := sg.opts.Language.Validate(); err != nil {
+		return err
+	}
+
+	tmpl, err := sg.html.templates()
+	if err != nil {
+		return err
+	}
+
+	err = writeSitePage(dir, siteStylesheet, func(w io.Writer) error {
+		_, err := io.WriteString(w, reportCSS+sg.opts.Buckets.css())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	err = writeSitePage(dir, siteScript, func(w io.Writer) error {
+		return tmpl.ExecuteTemplate(w, "sitescript", nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := sg.writePage(tmpl, dir, siteIndexPage, sg.indexData()); err != nil {
+		return err
+	}
+
+	for _, pkg := range sg.coverage.GetPackages() {
+		if err := sg.writePage(tmpl, dir, packagePage(pkg.ImportPath), sg.packageData(pkg)); err != nil {
+			return err
+		}
+		for _, file := range pkg.Files {
+			if err := sg.writePage(tmpl, dir, filePage(file.FilePath), sg.fileData(pkg, file)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writePage renderiza uma página com o bloco "site". As URLs de data são
+// montadas relativas à raiz do site e convertidas aqui para a página.
+func (sg *SiteGenerator) writePage(tmpl *template.Template, dir, page string, data *SitePage) error {
+	data.Language = sg.opts.Language.resolve()
+	data.Stylesheet = siteLink(page, siteStylesheet)
+	data.Script = siteLink(page, siteScript)
+	for i, crumb := range data.Crumbs {
+		if crumb.URL != "" {
+			data.Crumbs[i].URL = siteLink(page, crumb.URL)
+		}
+	}
+	for _, entries := range [][]SiteEntry{data.Packages, data.Files} {
+		for i := range entries {
+			entries[i].URL = siteLink(page, entries[i].URL)
+		}
+	}
+
+	return writeSitePage(dir, page, func(w io.Writer) error {
+		return tmpl.ExecuteTemplate(w, "site", data)
+	})
+}
+
+// indexData monta a página inicial
+func (sg *SiteGenerator) indexData() *SitePage {
+	report := sg.html.summary()
+	data := &SitePage{
+		Kind:     SitePageIndex,
+		Title:    report.Title,
+		Coverage: report.Total,
+		Report:   report,
+	}
+
+	for _, pkg := range sg.coverage.GetPackages() {
+		data.Packages = append(data.Packages, SiteEntry{
+			Name:     pkg.ImportPath,
+			URL:      packagePage(pkg.ImportPath),
+			Files:    len(pkg.Files),
+			Coverage: sg.html.htmlCoverage(pkg.Coverage, pkg.CoveredStmt, pkg.TotalStmt),
+			Delta:    sg.packageDelta(pkg.ImportPath),
+		})
+	}
+	return data
+}
+
+// packageData monta a página de um pacote
+func (sg *SiteGenerator) packageData(pkg *PackageCoverage) *SitePage {
+	data := &SitePage{
+		Kind:     SitePagePackage,
+		Title:    pkg.ImportPath,
+		Coverage: sg.html.htmlCoverage(pkg.Coverage, pkg.CoveredStmt, pkg.TotalStmt),
+		Crumbs: []SiteLink{
+			{Name: sg.opts.Language.text("Projeto"), URL: siteIndexPage},
+			{Name: pkg.ImportPath},
+		},
+	}
+
+	for _, file := range pkg.Files {
+		data.Files = append(data.Files, SiteEntry{
+			Name:     file.FileName,
+			URL:      filePage(file.FilePath),
+			Coverage: sg.html.htmlCoverage(file.Coverage, file.CoveredStmt, file.TotalStmt),
+			Delta:    sg.html.fileDelta(file.FilePath),
+		})
+	}
+	return data
+}
+
+// fileData monta a página de um arquivo
+func (sg *SiteGenerator) fileData(pkg *PackageCoverage, file *FileCoverage) *SitePage {
+	data := &SitePage{
+		Kind:     SitePageFile,
+		Title:    file.FileName,
+		Coverage: sg.html.htmlCoverage(file.Coverage, file.CoveredStmt, file.TotalStmt),
+		Crumbs: []SiteLink{
+			{Name: sg.opts.Language.text("Projeto"), URL: siteIndexPage},
+			{Name: pkg.ImportPath, URL: packagePage(pkg.ImportPath)},
+			{Name: file.FileName},
+		},
+		File: &SiteFile{Name: file.FileName, Path: file.FilePath},
+	}
+
+	data.File.Lines, data.File.SourceMissing = sg.sourceLines(file)
+	for _, fn := range sg.opts.Functions {
+		if fn.FilePath == file.FilePath {
+			data.File.Functions = append(data.File.Functions, sg.html.htmlFunction(fn))
+		}
+	}
+	return data
+}
+
+// sourceLines retorna o código do arquivo com os trechos cobertos e não
+// cobertos, como o loadFile do relatório de página única. Sem o código,
+// retorna apenas as linhas instrumentadas e missing verdadeiro.
+func (sg *SiteGenerator) sourceLines(file *FileCoverage) (lines []SiteLine, missing bool) {
+	coverage := lineCoverage(file.Blocks)
+
+	changed := make(map[int]bool)
+	if sg.opts.Patch != nil {
+		if fpc, ok := sg.opts.Patch.FilePatch(file.FilePath); ok {
+			for _, line := range fpc.ChangedLines {
+				changed[line] = true
+			}
+		}
+	}
+
+	lineClass := func(line int) string {
+		class := ""
+		if count, ok := coverage[line]; ok {
+			class = "uncovered"
+			if count == 1 {
+				class = "covered"
+			}
+		}
+		if changed[line] {
+			class += " changed"
+		}
+		return class
+	}
+
+	source := sg.html.sourceLines(file)
+	if source == nil {
+		lastLine := 0
+		for line := range coverage {
+			lastLine = max(lastLine, line)
+		}
+		for line := 1; line <= lastLine; line++ {
+			lines = append(lines, SiteLine{Number: line, Class: lineClass(line)})
+		}
+		return lines, true
+	}
+
+	segmentClasses := map[int]string{segmentCovered: "segment-covered", segmentUncovered: "segment-uncovered"}
+	for i, segments := range source {
+		line := SiteLine{Number: i + 1, Class: lineClass(i + 1)}
+		for _, segment := range segments {
+			line.Segments = append(line.Segments, SiteSegment{Text: segment.Text, Class: segmentClasses[segment.State]})
+		}
+		lines = append(lines, line)
+	}
+	return lines, false
+}
+
+// packageDelta retorna a variação de um pacote em relação à base
+func (sg *SiteGenerator) packageDelta(importPath string) *HTMLDelta {
+	if sg.opts.Diff == nil {
+		return nil
+	}
+	for _, delta := range sg.opts.Diff.Packages {
+		if delta.Path == importPath {
+			return &HTMLDelta{Value: delta.Delta, Class: deltaClass(delta.Delta), New: delta.Status == DeltaAdded}
+		}
+	}
+	return nil
+}
+
+// writeSitePage cria a página (e seus diretórios) dentro do site
+func writeSitePage(dir, page string, write func(io.Writer) error) error {
+	target := filepath.Join(dir, filepath.FromSlash(page))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório do site: %w", err)
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("erro ao criar página %s: %w", page, err)
+	}
+
+	buffered := bufio.NewWriter(file)
+	if err := write(buffered); err != nil {
+		file.Close()
+		return fmt.Errorf("erro ao gerar página %s: %w", page, err)
+	}
+	if err := buffered.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("erro ao gerar página %s: %w", page, err)
+	}
+	return file.Close()
+}
+
+// packagePage retorna a página de um pacote, relativa à raiz do site
+func packagePage(importPath string) string {
+	return path.Join(sitePackagesDir, sitePath(importPath), siteIndexPage)
+}
+
+// filePage retorna a página de um arquivo, relativa à raiz do site
+func filePage(filePath string) string {
+	return path.Join(siteFilesDir, sitePath(filePath)) + ".html"
+}
+
+// sitePath converte um caminho do perfil em um caminho seguro dentro do
+// site. Cada segmento mantém letras, dígitos e ".-_~+@"; os demais bytes
+// viram %XX, o que torna a conversão injetiva e impede que "..", caminhos
+// absolutos ou nomes de drive como "C:" escapem do diretório.
+func sitePath(p string) string {
+	var segments []string
+	for _, segment := range strings.Split(p, "/") {
+		if segment == "" {
+			continue
+		}
+		if segment == "." || segment == ".." {
+			segments = append(segments, strings.Repeat("%2E", len(segment)))
+			continue
+		}
+
+		var sb strings.Builder
+		for _, r := range segment {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune(".-_~+@", r) {
+				sb.WriteRune(r)
+				continue
+			}
+			for _, b := range []byte(string(r)) {
+				fmt.Fprintf(&sb, "%%%02X", b)
+			}
+		}
+		segments = append(segments, sb.String())
+	}
+
+	if len(segments) == 0 {
+		return "_"
+	}
+	return strings.Join(segments, "/")
+}
+
+// siteLink retorna a URL relativa de from até to, ambos relativos à raiz do
+// site
+func siteLink(from, to string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.Repeat("../", strings.Count(from, "/")))
+	for i, segment := range strings.Split(to, "/") {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+		sb.WriteString(url.PathEscape(segment))
+	}
+	return sb.String()
+}
@@ -0,0 +1,222 @@
+package coverage
+
+import (
+	"html"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSiteGenerator(t *testing.T) {
+	root := writeTestModule(t)
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cov := mustParse(t, `mode: set
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 1
+example.com/demo/pkg/calc/sum.go:4.11,6.3 1 0
+example.com/demo/pkg/calc/sum.go:7.2,7.14 1 1
+example.com/demo/main.go:3.13,5.2 2 0
+`)
+	functions, err := AnalyzeFunctions(cov, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "site")
+	if err := NewSiteGenerator(cov, HTMLOptions{Sources: resolver, Functions: functions}).Generate(dir); err != nil {
+		t.Fatalf("erro ao gerar site: %v", err)
+	}
+
+	pages := map[string][]string{
+		"index.html": {
+			`<a href="packages/example.com/demo/pkg/calc/index.html">📁 example.com/demo/pkg/calc</a>`,
+			`<link rel="stylesheet" href="assets/style.css">`,
+		},
+		"packages/example.com/demo/pkg/calc/index.html": {
+			`<a href="../../../../../files/example.com/demo/pkg/calc/sum.go.html">📄 sum.go</a>`,
+			`<link rel="stylesheet" href="../../../../../assets/style.css">`,
+		},
+		"files/example.com/demo/pkg/calc/sum.go.html": {
+			`<span class="segment-uncovered">{`,
+			`<td><code>Sum</code></td>`,
+			`<nav class="breadcrumb"><a href="../../../../../index.html">Projeto</a>`,
+		},
+		"files/example.com/demo/main.go.html": {
+			"Código-fonte não disponível",
+		},
+		"assets/site.js": {
+			"function sortableTable(table) {",
+			"document.querySelectorAll('.report-table').forEach(sortableTable);",
+		},
+	}
+	for page, wants := range pages {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(page)))
+		if err != nil {
+			t.Errorf("página %s não gerada: %v", page, err)
+			continue
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s não contém %q", page, want)
+			}
+		}
+		if strings.Contains(string(data), `id="reportData"`) {
+			t.Errorf("%s não deveria embutir os dados de todos os arquivos", page)
+		}
+	}
+
+	assertSiteLinks(t, dir)
+}
+
+func TestSiteTemplateOverrides(t *testing.T) {
+	base := mustParse(t, "mode: set\nexample.com/app/a.go:1.1,2.2 2 0\n")
+	cov := mustParse(t, "mode: set\nexample.com/app/a.go:1.1,2.2 2 1\nexample.com/app/novo/b.go:1.1,2.2 1 0\n")
+
+	opts := HTMLOptions{
+		Diff: CompareCoverage(base, cov),
+		Templates: fstest.MapFS{
+			"tema.html": {Data: []byte(`{{define "badge"}}<b class="{{.Class}}">{{percent .Percent}}</b>{{end}}`)},
+		},
+		Blocks: map[string]string{
+			"siteheader": `<header class="tema">{{.Title}} · {{.Kind}}{{range .Crumbs}} · {{.Name}}{{end}}</header>`,
+			"tablesort":  `function sortableTable(table) {} // tema`,
+		},
+	}
+	dir := t.TempDir()
+	if err := NewSiteGenerator(cov, opts).Generate(dir); err != nil {
+		t.Fatalf("erro ao gerar site: %v", err)
+	}
+
+	pages := map[string][]string{
+		"index.html": {
+			`<header class="tema">Relatório de Cobertura de Testes · index</header>`,
+			`<b class="coverage-excellent">100,0%</b><span class="file-delta delta-up">&#43;100,0</span>`,
+			`<b class="coverage-poor">0,0%</b><span class="file-delta delta-none">novo</span>`,
+			`<div class="stat-label">Variação vs. Base</div>`,
+		},
+		"packages/example.com/app/index.html": {
+			`<header class="tema">example.com/app · package · Projeto · example.com/app</header>`,
+			`<td><a href="../../../files/example.com/app/a.go.html">📄 a.go</a></td>`,
+		},
+		"files/example.com/app/a.go.html": {
+			`<header class="tema">a.go · file · Projeto · example.com/app · a.go</header>`,
+		},
+		"assets/site.js": {
+			"function sortableTable(table) {} // tema",
+		},
+	}
+	for page, wants := range pages {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(page)))
+		if err != nil {
+			t.Fatalf("página %s não gerada: %v", page, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("%s não contém %q:\n%s", page, want, data)
+			}
+		}
+	}
+
+	opts.Blocks = map[string]string{"sitefile": "{{.Autor}}"}
+	if err := NewSiteGenerator(cov, opts).Generate(t.TempDir()); err == nil || !strings.Contains(err.Error(), "Autor") {
+		t.Errorf("erro = %v, esperado erro do bloco sitefile", err)
+	}
+}
+
+func TestSitePath(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"example.com/demo/pkg", "example.com/demo/pkg"},
+		{"/abs/path/x.go", "abs/path/x.go"},
+		{"../../etc/passwd", "%2E%2E/%2E%2E/etc/passwd"},
+		{"C:/work/app.go", "C%3A/work/app.go"},
+		{"dir with space/ação.go", "dir%20with%20space/ação.go"},
+		{"a%2Fb.go", "a%252Fb.go"},
+		{"", "_"},
+	}
+
+	for _, tt := range tests {
+		if got := sitePath(tt.in); got != tt.want {
+			t.Errorf("sitePath(%q) = %q, esperado %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSiteHostilePaths(t *testing.T) {
+	builder := newProfileBuilder(ModeSet)
+	for _, p := range []string{"../../fora.go", "dir com espaço/<script>.go", "C:/work/app.go"} {
+		if err := builder.add(p, CoverageBlock{StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cov := builder.finish()
+
+	base := t.TempDir()
+	dir := filepath.Join(base, "site")
+	if err := NewSiteGenerator(cov, HTMLOptions{}).Generate(dir); err != nil {
+		t.Fatalf("erro ao gerar site: %v", err)
+	}
+
+	entries, _ := os.ReadDir(base)
+	if len(entries) != 1 {
+		t.Errorf("o site escreveu fora do diretório de saída: %v", entries)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "<script>.go") {
+		t.Error("caminho não escapado na página inicial")
+	}
+
+	assertSiteLinks(t, dir)
+}
+
+// assertSiteLinks verifica que todos os links relativos do site apontam para
+// arquivos gerados
+func assertSiteLinks(t *testing.T, dir string) {
+	t.Helper()
+	link := regexp.MustCompile(`(?:href|src)="([^"]+)"`)
+
+	err := filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".html") {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		page, _ := filepath.Rel(dir, p)
+		for _, m := range link.FindAllStringSubmatch(string(data), -1) {
+			target, err := url.PathUnescape(html.UnescapeString(m[1]))
+			if err != nil {
+				t.Errorf("%s: link inválido %q", page, m[1])
+				continue
+			}
+			resolved := path.Join(path.Dir(filepath.ToSlash(page)), target)
+			if strings.HasPrefix(resolved, "../") {
+				t.Errorf("%s: link %q sai do site", page, m[1])
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(resolved))); err != nil {
+				t.Errorf("%s: link quebrado %q", page, m[1])
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,197 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SourceResolver localiza no disco os arquivos referenciados no perfil de
+// cobertura. Os caminhos do perfil usam o caminho de import do módulo
+// (ex.: github.com/org/repo/pkg/file.go), que é convertido para um caminho
+// relativo ao diretório raiz do módulo.
+type SourceResolver struct {
+	// Root é o diretório raiz do módulo. Arquivos fora dele nunca são lidos.
+	Root string
+	// ModulePath é o caminho do módulo declarado no go.mod
+	ModulePath string
+}
+
+// NewSourceResolver cria um resolver para o diretório root. Se root contém
+// um go.mod, o caminho do módulo é lido dele.
+func NewSourceResolver(root string) (*SourceResolver, error) {
+	if root == "" {
+		root = "."
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao resolver diretório raiz: %w", err)
+	}
+
+	modulePath, err := readModulePath(filepath.Join(absRoot, "go.mod"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	return &SourceResolver{Root: absRoot, ModulePath: modulePath}, nil
+}
+
+// readModulePath extrai a diretiva module de um go.mod
+func readModulePath(goModPath string) (string, error) {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(fields[1]); err == nil {
+			return unquoted, nil
+		}
+		return fields[1], nil
+	}
+
+	return "", fmt.Errorf("diretiva module não encontrada em %s", goModPath)
+}
+
+// Resolve retorna o caminho local do arquivo referenciado no perfil
+func (sr *SourceResolver) Resolve(filePath string) (string, error) {
+	var candidates []string
+
+	if filepath.IsAbs(filePath) {
+		candidates = append(candidates, filepath.Clean(filePath))
+	}
+
+	if sr.ModulePath != "" {
+		if rest, ok := strings.CutPrefix(filePath, sr.ModulePath+"/"); ok {
+			candidates = append(candidates, filepath.Join(sr.Root, filepath.FromSlash(rest)))
+		}
+	}
+
+	candidates = append(candidates, filepath.Join(sr.Root, filepath.FromSlash(filePath)))
+
+	for _, candidate := range candidates {
+		if !sr.contains(candidate) {
+			continue
+		}
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("código-fonte não encontrado: %s", filePath)
+}
+
+// contains indica se path está dentro do diretório raiz
+func (sr *SourceResolver) contains(path string) bool {
+	rel, err := filepath.Rel(sr.Root, path)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ReadSource lê o código-fonte do arquivo referenciado no perfil
+func (sr *SourceResolver) ReadSource(filePath string) ([]byte, error) {
+	localPath, err := sr.Resolve(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(localPath)
+}
+
+// Estados de cobertura de um trecho de código
+const (
+	segmentNone      = -1 // fora de qualquer bloco
+	segmentUncovered = 0
+	segmentCovered   = 1
+)
+
+// sourceSegment é um trecho contínuo de uma linha com o mesmo estado
+type sourceSegment struct {
+	Text  string
+	State int
+}
+
+// annotateSource divide cada linha do código em trechos de acordo com as
+// faixas linha.coluna dos blocos. As colunas do perfil são offsets em bytes
+// iniciando em 1 e a coluna final é exclusiva. Quando blocos se sobrepõem,
+// o estado coberto prevalece.
+func annotateSource(src []byte, blocks []CoverageBlock) [][]sourceSegment {
+	text := strings.ReplaceAll(string(src), "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	lines := strings.Split(text, "\n")
+
+	result := make([][]sourceSegment, len(lines))
+	for i, line := range lines {
+		lineNum := i + 1
+
+		states := make([]int, len(line))
+		for j := range states {
+			states[j] = segmentNone
+		}
+
+		for _, block := range blocks {
+			if lineNum < block.StartLine || lineNum > block.EndLine {
+				continue
+			}
+
+			start := 0
+			if lineNum == block.StartLine {
+				start = block.StartCol - 1
+			}
+			end := len(line)
+			if lineNum == block.EndLine {
+				end = block.EndCol - 1
+			}
+			start = max(start, 0)
+			end = min(end, len(line))
+
+			for j := start; j < end; j++ {
+				if block.Count > 0 {
+					states[j] = segmentCovered
+				} else if states[j] != segmentCovered {
+					states[j] = segmentUncovered
+				}
+			}
+		}
+
+		result[i] = splitSegments(line, states)
+	}
+
+	return result
+}
+
+// splitSegments agrupa bytes consecutivos com o mesmo estado
+func splitSegments(line string, states []int) []sourceSegment {
+	segments := []sourceSegment{}
+	start := 0
+	for j := 1; j <= len(line); j++ {
+		if j < len(line) && states[j] == states[start] {
+			continue
+		}
+		segments = append(segments, sourceSegment{Text: line[start:j], State: states[start]})
+		start = j
+	}
+	return segments
+}
+
+// MarshalJSON serializa o trecho no formato compacto [texto, estado]
+func (s sourceSegment) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{s.Text, s.State})
+}
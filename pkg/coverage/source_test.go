@@ -0,0 +1,153 @@
+package coverage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestModule(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod":          "module example.com/demo // comentário\n\ngo 1.24\n",
+		"pkg/calc/sum.go": "package calc\n\nfunc Sum(a, b int) int {\n\tif a < 0 {\n\t\treturn b\n\t}\n\treturn a + b\n}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestNewSourceResolverReadsGoMod(t *testing.T) {
+	root := writeTestModule(t)
+
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatalf("erro ao criar resolver: %v", err)
+	}
+
+	if resolver.ModulePath != "example.com/demo" {
+		t.Errorf("módulo esperado 'example.com/demo', obtido '%s'", resolver.ModulePath)
+	}
+}
+
+func TestSourceResolverResolve(t *testing.T) {
+	root := writeTestModule(t)
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		wantErr  bool
+	}{
+		{"caminho do módulo", "example.com/demo/pkg/calc/sum.go", false},
+		{"caminho relativo à raiz", "pkg/calc/sum.go", false},
+		{"caminho absoluto dentro da raiz", filepath.Join(root, "pkg", "calc", "sum.go"), false},
+		{"arquivo inexistente", "example.com/demo/pkg/calc/missing.go", true},
+		{"outro módulo", "example.com/other/pkg/calc/sum.go", true},
+		{"fora da raiz", "../../etc/passwd", true},
+		{"diretório", "example.com/demo/pkg/calc", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.filePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != filepath.Join(root, "pkg", "calc", "sum.go") {
+				t.Errorf("caminho = %s", got)
+			}
+		})
+	}
+}
+
+func TestAnnotateSource(t *testing.T) {
+	src := []byte("func f() {\n\tif x {\n\t\ty()\n\t}\n}\n")
+	blocks := []CoverageBlock{
+		{StartLine: 1, StartCol: 10, EndLine: 2, EndCol: 8, NumStmt: 1, Count: 1},
+		{StartLine: 2, StartCol: 8, EndLine: 4, EndCol: 3, NumStmt: 1, Count: 0},
+	}
+
+	lines := annotateSource(src, blocks)
+	if len(lines) != 5 {
+		t.Fatalf("esperava 5 linhas, obteve %d", len(lines))
+	}
+
+	want := [][]sourceSegment{
+		{{"func f() ", segmentNone}, {"{", segmentCovered}},
+		{{"\tif x {", segmentCovered}},
+		{{"\t\ty()", segmentUncovered}},
+		{{"\t}", segmentUncovered}},
+		{{"}", segmentNone}},
+	}
+
+	for i := range want {
+		if len(lines[i]) != len(want[i]) {
+			t.Errorf("linha %d: trechos = %v, esperado %v", i+1, lines[i], want[i])
+			continue
+		}
+		for j := range want[i] {
+			if lines[i][j] != want[i][j] {
+				t.Errorf("linha %d trecho %d = %v, esperado %v", i+1, j, lines[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestAnnotateSourceClampsColumns(t *testing.T) {
+	lines := annotateSource([]byte("x\r\n"), []CoverageBlock{
+		{StartLine: 1, StartCol: 0, EndLine: 3, EndCol: 99, NumStmt: 1, Count: 1},
+	})
+
+	if len(lines) != 1 || len(lines[0]) != 1 || lines[0][0] != (sourceSegment{"x", segmentCovered}) {
+		t.Errorf("trechos inesperados: %v", lines)
+	}
+}
+
+func TestHTMLGenerationWithSources(t *testing.T) {
+	root := writeTestModule(t)
+	resolver, err := NewSourceResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := `mode: set
+example.com/demo/pkg/calc/sum.go:3.24,4.11 1 1
+example.com/demo/pkg/calc/sum.go:4.11,6.3 1 0
+example.com/demo/pkg/calc/missing.go:1.1,2.2 1 1
+`
+	cov, err := ParseCoverageFile(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewHTMLGeneratorWithOptions(cov, HTMLOptions{Sources: resolver}).Generate(&buf); err != nil {
+		t.Fatalf("erro ao gerar HTML: %v", err)
+	}
+
+	html := buf.String()
+	if !strings.Contains(html, `"\treturn a + b",-1`) {
+		t.Error("HTML não contém o código-fonte real")
+	}
+	if strings.Contains(html, "// Linha") {
+		t.Error("HTML ainda contém linhas simuladas")
+	}
+	if !strings.Contains(html, `"lines":null`) {
+		t.Error(`arquivo sem código-fonte deveria ter "lines": null`)
+	}
+}
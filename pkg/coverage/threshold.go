@@ -0,0 +1,214 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ThresholdRules define as coberturas mínimas exigidas do projeto.
+//
+// Exemplo de arquivo de regras:
+//
+//	{
+//	  "total": 80,
+//	  "packages": [
+//	    {"pattern": "example.com/app/**", "min": 70},
+//	    {"pattern": "example.com/app/internal/legacy", "min": 30}
+//	  ],
+//	  "files": [
+//	    {"pattern": "*_handler.go", "min": 60}
+//	  ]
+//	}
+//
+// Quando várias regras casam com o mesmo pacote ou arquivo, vale a última,
+// o que permite declarar exceções depois das regras gerais.
+type ThresholdRules struct {
+	// Total é a cobertura mínima do projeto (0 desativa a verificação)
+	Total float64 `json:"total"`
+	// Packages são regras aplicadas ao diretório de cada arquivo
+	Packages []ThresholdRule `json:"packages,omitempty"`
+	// Files são regras aplicadas ao caminho de cada arquivo
+	Files []ThresholdRule `json:"files,omitempty"`
+}
+
+// ThresholdRule associa um padrão glob a uma cobertura mínima. O padrão
+// aceita *, ? e ** e, quando não contém "/", é comparado apenas com o
+// nome base.
+type ThresholdRule struct {
+	Pattern string  `json:"pattern"`
+	Min     float64 `json:"min"`
+}
+
+// Escopos de uma violação de limite
+const (
+	ScopeTotal   = "total"
+	ScopePackage = "package"
+	ScopeFile    = "file"
+	ScopePatch   = "patch" // linhas alteradas de um diff (ver PatchCoverage)
+)
+
+// ThresholdViolation descreve um alvo abaixo da cobertura mínima
+type ThresholdViolation struct {
+	Scope   string  // ScopeTotal, ScopePatch, ScopePackage ou ScopeFile
+	Target  string  // pacote ou arquivo (vazio para o total e o patch)
+	Pattern string  // padrão da regra que gerou a violação
+	Min     float64 // cobertura mínima exigida
+	Actual  float64 // cobertura obtida
+}
+
+// String formata a violação em uma linha legível em DefaultLanguage
+func (v ThresholdViolation) String() string {
+	return v.Localized(DefaultLanguage)
+}
+
+// Localized formata a violação em uma linha legível no idioma informado
+func (v ThresholdViolation) Localized(lang Language) string {
+	actual, min := lang.percent(v.Actual), lang.percent(v.Min)
+	switch v.Scope {
+	case ScopeTotal:
+		return lang.tr("cobertura total %s abaixo do mínimo de %s", actual, min)
+	case ScopePatch:
+		return lang.tr("cobertura do patch %s abaixo do mínimo de %s", actual, min)
+	case ScopePackage:
+		return lang.tr("pacote %s: %s abaixo do mínimo de %s (regra %q)", v.Target, actual, min, v.Pattern)
+	}
+	return lang.tr("arquivo %s: %s abaixo do mínimo de %s (regra %q)", v.Target, actual, min, v.Pattern)
+}
+
+// WriteViolations escreve um resumo das violações de limite no idioma
+// informado, uma por linha
+func WriteViolations(w io.Writer, violations []ThresholdViolation, lang Language) error {
+	var sb strings.Builder
+	sb.WriteString(lang.plural(len(violations), "❌ Cobertura abaixo do mínimo (%d violação):", "❌ Cobertura abaixo do mínimo (%d violações):"))
+	sb.WriteString("\n")
+	for _, v := range violations {
+		fmt.Fprintf(&sb, "  - %s\n", v.Localized(lang))
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// LoadThresholdRules lê regras de limite em JSON. Campos desconhecidos e
+// padrões inválidos são rejeitados.
+func LoadThresholdRules(reader io.Reader) (*ThresholdRules, error) {
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+
+	rules := &ThresholdRules{}
+	if err := decoder.Decode(rules); err != nil {
+		return nil, fmt.Errorf("erro ao ler regras de limite: %w", err)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// Validate verifica os percentuais e padrões das regras
+func (tr *ThresholdRules) Validate() error {
+	if tr.Total < 0 || tr.Total > 100 {
+		return fmt.Errorf("limite total inválido: %.1f", tr.Total)
+	}
+
+	for _, rule := range append(append([]ThresholdRule{}, tr.Packages...), tr.Files...) {
+		if rule.Min < 0 || rule.Min > 100 {
+			return fmt.Errorf("limite inválido para %q: %.1f", rule.Pattern, rule.Min)
+		}
+		if _, err := compileGlob(rule.Pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckThresholds compara a cobertura com as regras e retorna as violações
+// ordenadas por escopo (total, pacotes, arquivos) e caminho. Pacotes e
+// arquivos sem statements são ignorados. Regras nil não geram violações.
+func CheckThresholds(coverage *ProjectCoverage, rules *ThresholdRules) ([]ThresholdViolation, error) {
+	if rules == nil {
+		return []ThresholdViolation{}, nil
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	violations := []ThresholdViolation{}
+
+	if rules.Total > 0 {
+		if total := coverage.GetTotalCoverage(); total < rules.Total {
+			violations = append(violations, ThresholdViolation{
+				Scope:  ScopeTotal,
+				Min:    rules.Total,
+				Actual: total,
+			})
+		}
+	}
+
+	packages := make(map[string]*FileCoverage)
+	for _, pkg := range coverage.GetPackages() {
+		packages[pkg.ImportPath] = &FileCoverage{
+			FilePath:    pkg.ImportPath,
+			TotalStmt:   pkg.TotalStmt,
+			CoveredStmt: pkg.CoveredStmt,
+		}
+	}
+
+	violations = append(violations, checkRules(ScopePackage, packages, rules.Packages)...)
+	violations = append(violations, checkRules(ScopeFile, coverage.Files, rules.Files)...)
+
+	return violations, nil
+}
+
+// checkRules aplica a última regra que casa com cada alvo
+func checkRules(scope string, targets map[string]*FileCoverage, rules []ThresholdRule) []ThresholdViolation {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	matchers := make([]*globMatcher, len(rules))
+	for i, rule := range rules {
+		// padrões já validados em Validate
+		matchers[i], _ = compileGlob(rule.Pattern)
+	}
+
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var violations []ThresholdViolation
+	for _, name := range names {
+		target := targets[name]
+		if target.TotalStmt == 0 {
+			continue
+		}
+
+		ruleIdx := -1
+		for i, matcher := range matchers {
+			if matcher.Match(name) {
+				ruleIdx = i
+			}
+		}
+		if ruleIdx < 0 {
+			continue
+		}
+
+		actual := float64(target.CoveredStmt) / float64(target.TotalStmt) * 100
+		if actual < rules[ruleIdx].Min {
+			violations = append(violations, ThresholdViolation{
+				Scope:   scope,
+				Target:  name,
+				Pattern: rules[ruleIdx].Pattern,
+				Min:     rules[ruleIdx].Min,
+				Actual:  actual,
+			})
+		}
+	}
+	return violations
+}
@@ -0,0 +1,172 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const thresholdProfile = `mode: set
+example.com/app/api/handler.go:1.1,2.2 10 1
+example.com/app/api/router.go:1.1,2.2 10 0
+example.com/app/core/model.go:1.1,2.2 10 1
+example.com/app/core/model_gen.go:1.1,2.2 10 0
+example.com/app/legacy/old.go:1.1,2.2 10 0
+`
+
+func TestCheckThresholds(t *testing.T) {
+	cov := mustParse(t, thresholdProfile)
+
+	rules, err := LoadThresholdRules(strings.NewReader(`{
+		"total": 50,
+		"packages": [
+			{"pattern": "example.com/app/**", "min": 60},
+			{"pattern": "example.com/app/legacy", "min": 0}
+		],
+		"files": [
+			{"pattern": "*_gen.go", "min": 10},
+			{"pattern": "example.com/app/api/*.go", "min": 40}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("erro ao ler regras: %v", err)
+	}
+
+	violations, err := CheckThresholds(cov, rules)
+	if err != nil {
+		t.Fatalf("erro ao verificar limites: %v", err)
+	}
+
+	want := []ThresholdViolation{
+		{Scope: ScopeTotal, Min: 50, Actual: 40},
+		{Scope: ScopePackage, Target: "example.com/app/api", Pattern: "example.com/app/**", Min: 60, Actual: 50},
+		{Scope: ScopePackage, Target: "example.com/app/core", Pattern: "example.com/app/**", Min: 60, Actual: 50},
+		{Scope: ScopeFile, Target: "example.com/app/api/router.go", Pattern: "example.com/app/api/*.go", Min: 40, Actual: 0},
+		{Scope: ScopeFile, Target: "example.com/app/core/model_gen.go", Pattern: "*_gen.go", Min: 10, Actual: 0},
+	}
+
+	if len(violations) != len(want) {
+		t.Fatalf("esperava %d violações, obteve %d: %v", len(want), len(violations), violations)
+	}
+	for i := range want {
+		if violations[i] != want[i] {
+			t.Errorf("violação %d = %+v, esperado %+v", i, violations[i], want[i])
+		}
+	}
+}
+
+func TestCheckThresholdsPasses(t *testing.T) {
+	cov := mustParse(t, thresholdProfile)
+
+	violations, err := CheckThresholds(cov, &ThresholdRules{Total: 40})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("não esperava violações, obteve %v", violations)
+	}
+
+	violations, err = CheckThresholds(cov, nil)
+	if err != nil || len(violations) != 0 {
+		t.Errorf("regras nil: violações %v, erro %v", violations, err)
+	}
+}
+
+func TestLoadThresholdRulesErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"campo desconhecido", `{"totl": 80}`},
+		{"limite fora do intervalo", `{"total": 120}`},
+		{"padrão vazio", `{"files": [{"pattern": "", "min": 10}]}`},
+		{"json inválido", `{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadThresholdRules(strings.NewReader(tt.input)); err == nil {
+				t.Error("esperava erro")
+			}
+		})
+	}
+}
+
+func TestThresholdViolationString(t *testing.T) {
+	total := ThresholdViolation{Scope: ScopeTotal, Min: 80, Actual: 72.25}
+	if got := total.String(); got != "cobertura total 72,2% abaixo do mínimo de 80,0%" {
+		t.Errorf("String() = %q", got)
+	}
+
+	file := ThresholdViolation{Scope: ScopeFile, Target: "pkg/a.go", Pattern: "*.go", Min: 50, Actual: 10}
+	if got := file.String(); !strings.Contains(got, "arquivo pkg/a.go") || !strings.Contains(got, `"*.go"`) {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestGlobMatcher(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "pkg/a/file.go", true},
+		{"*_gen.go", "pkg/a/model_gen.go", true},
+		{"pkg/*", "pkg/a", true},
+		{"pkg/*", "pkg/a/b", false},
+		{"pkg/**", "pkg/a/b", true},
+		{"pkg/**/file.go", "pkg/file.go", true},
+		{"pkg/**/file.go", "pkg/a/b/file.go", true},
+		{"pkg/?.go", "pkg/a.go", true},
+		{"pkg/?.go", "pkg/ab.go", false},
+		{"vendor/**", "example.com/vendor/x.go", false},
+		{"**/vendor/**", "example.com/vendor/x.go", true},
+		{"a.b/*.go", "axb/c.go", false},
+		{"pkg/ação/*.go", "pkg/ação/x.go", true},
+		{"açúcar_*.go", "pkg/açúcar_doce.go", true},
+		{"pkg/?.go", "pkg/ç.go", true},
+		{"pkg/ação/*.go", "pkg/acao/x.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			matcher, err := compileGlob(tt.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := matcher.Match(tt.name); got != tt.want {
+				t.Errorf("Match(%q) = %v, esperado %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThresholdViolationLocalized(t *testing.T) {
+	violations := []ThresholdViolation{
+		{Scope: ScopeTotal, Min: 80, Actual: 72.25},
+		{Scope: ScopePackage, Target: "pkg", Pattern: "pkg", Min: 50, Actual: 10},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteViolations(&buf, violations, LanguageEnglish); err != nil {
+		t.Fatal(err)
+	}
+	want := "❌ Coverage below the minimum (2 violations):\n" +
+		"  - total coverage 72.2% below the minimum of 80.0%\n" +
+		"  - package pkg: 10.0% below the minimum of 50.0% (rule \"pkg\")\n"
+	if buf.String() != want {
+		t.Errorf("WriteViolations = %q\nesperado %q", buf.String(), want)
+	}
+
+	if got := violations[0].Localized(LanguageSpanish); got != "cobertura total 72,2% por debajo del mínimo de 80,0%" {
+		t.Errorf("Localized(es) = %q", got)
+	}
+
+	buf.Reset()
+	if err := WriteViolations(&buf, violations[:1], ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "❌ Cobertura abaixo do mínimo (1 violação):\n") {
+		t.Errorf("WriteViolations com uma violação = %q", buf.String())
+	}
+}
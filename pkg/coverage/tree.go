@@ -0,0 +1,119 @@
+package coverage
+
+import (
+	"sort"
+	"strings"
+)
+
+// TreeNode é um diretório ou arquivo na árvore de pacotes do projeto.
+// Diretórios agregam os statements de todos os arquivos abaixo deles.
+type TreeNode struct {
+	// Name é o nome exibido. Diretórios com um único subdiretório e nenhum
+	// arquivo são compactados em um só nó (ex.: "github.com/org/repo").
+	Name string
+	// Path é o caminho completo do nó (igual ao FilePath para arquivos)
+	Path string
+	// File é a cobertura do arquivo; nil para diretórios
+	File *FileCoverage
+	// Children contém primeiro os diretórios e depois os arquivos, cada
+	// grupo ordenado por nome
+	Children []*TreeNode
+
+	TotalStmt   int
+	CoveredStmt int
+	Coverage    float64 // percentual de 0-100
+}
+
+// IsDir indica se o nó é um diretório
+func (tn *TreeNode) IsDir() bool {
+	return tn.File == nil
+}
+
+// FileCount retorna o número de arquivos abaixo do nó
+func (tn *TreeNode) FileCount() int {
+	if !tn.IsDir() {
+		return 1
+	}
+	count := 0
+	for _, child := range tn.Children {
+		count += child.FileCount()
+	}
+	return count
+}
+
+// BuildTree monta a árvore de diretórios a partir dos caminhos dos arquivos.
+// A raiz tem Name e Path vazios e agrega a cobertura total.
+func (pc *ProjectCoverage) BuildTree() *TreeNode {
+	root := &TreeNode{}
+
+	for _, file := range pc.GetSortedFiles() {
+		parts := strings.Split(file.FilePath, "/")
+		node := root
+		for i, part := range parts[:len(parts)-1] {
+			node = node.childDir(part, strings.Join(parts[:i+1], "/"))
+		}
+		node.Children = append(node.Children, &TreeNode{
+			Name: parts[len(parts)-1],
+			Path: file.FilePath,
+			File: file,
+		})
+	}
+
+	root.compact()
+	root.aggregate()
+	return root
+}
+
+func (tn *TreeNode) childDir(name, path string) *TreeNode {
+	for _, child := range tn.Children {
+		if child.IsDir() && child.Name == name {
+			return child
+		}
+	}
+	child := &TreeNode{Name: name, Path: path}
+	tn.Children = append(tn.Children, child)
+	return child
+}
+
+// compact junta cadeias de diretórios com um único filho diretório
+func (tn *TreeNode) compact() {
+	for _, child := range tn.Children {
+		for child.IsDir() && len(child.Children) == 1 && child.Children[0].IsDir() {
+			only := child.Children[0]
+			child.Name += "/" + only.Name
+			child.Path = only.Path
+			child.Children = only.Children
+		}
+		child.compact()
+	}
+
+	sort.SliceStable(tn.Children, func(i, j int) bool {
+		a, b := tn.Children[i], tn.Children[j]
+		if a.IsDir() != b.IsDir() {
+			return a.IsDir()
+		}
+		return a.Name < b.Name
+	})
+}
+
+// aggregate soma os statements dos filhos em cada diretório
+func (tn *TreeNode) aggregate() {
+	if !tn.IsDir() {
+		tn.TotalStmt = tn.File.TotalStmt
+		tn.CoveredStmt = tn.File.CoveredStmt
+		tn.Coverage = tn.File.Coverage
+		return
+	}
+
+	tn.TotalStmt, tn.CoveredStmt = 0, 0
+	for _, child := range tn.Children {
+		child.aggregate()
+		tn.TotalStmt += child.TotalStmt
+		tn.CoveredStmt += child.CoveredStmt
+	}
+
+	tn.Coverage = 0
+	if tn.TotalStmt > 0 {
+		tn.Coverage = float64(tn.CoveredStmt) / float64(tn.TotalStmt) * 100
+	}
+}
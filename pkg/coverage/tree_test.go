@@ -0,0 +1,85 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBuildTree(t *testing.T) {
+	cov := mustParse(t, `mode: set
+example.com/app/pkg/api/handler.go:1.1,2.2 4 1
+example.com/app/pkg/api/router.go:1.1,2.2 4 0
+example.com/app/pkg/core/model.go:1.1,2.2 2 1
+example.com/app/pkg/main.go:1.1,2.2 2 0
+`)
+
+	root := cov.BuildTree()
+	if root.TotalStmt != 12 || root.CoveredStmt != 6 || root.Coverage != 50 {
+		t.Errorf("raiz = %d/%d (%.1f%%)", root.CoveredStmt, root.TotalStmt, root.Coverage)
+	}
+	if root.FileCount() != 4 {
+		t.Errorf("esperava 4 arquivos, obteve %d", root.FileCount())
+	}
+
+	// example.com/app/pkg é compactado em um único nó
+	if len(root.Children) != 1 {
+		t.Fatalf("esperava 1 nó na raiz, obteve %d", len(root.Children))
+	}
+	pkg := root.Children[0]
+	if pkg.Name != "example.com/app/pkg" || pkg.Path != "example.com/app/pkg" || !pkg.IsDir() {
+		t.Errorf("nó compactado = %q (%q)", pkg.Name, pkg.Path)
+	}
+
+	var names []string
+	for _, child := range pkg.Children {
+		names = append(names, child.Name)
+	}
+	if got := strings.Join(names, ","); got != "api,core,main.go" {
+		t.Errorf("filhos = %s, esperado diretórios antes dos arquivos", got)
+	}
+
+	api := pkg.Children[0]
+	if api.Path != "example.com/app/pkg/api" || api.TotalStmt != 8 || api.CoveredStmt != 4 {
+		t.Errorf("api = %+v", api)
+	}
+
+	mainGo := pkg.Children[2]
+	if mainGo.IsDir() || mainGo.File.FilePath != "example.com/app/pkg/main.go" {
+		t.Errorf("main.go = %+v", mainGo)
+	}
+}
+
+func TestBuildTreeEmpty(t *testing.T) {
+	root := (&ProjectCoverage{Files: map[string]*FileCoverage{}}).BuildTree()
+	if len(root.Children) != 0 || root.TotalStmt != 0 || firstFile(root) != nil {
+		t.Errorf("árvore vazia inesperada: %+v", root)
+	}
+}
+
+func TestHTMLGenerationTree(t *testing.T) {
+	cov := mustParse(t, `mode: set
+pkg/a/one.go:1.1,2.2 1 1
+pkg/a/deep/two.go:1.1,2.2 1 0
+pkg/b/three.go:1.1,2.2 1 1
+`)
+
+	var buf bytes.Buffer
+	if err := NewHTMLGenerator(cov).Generate(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	html := buf.String()
+	for _, want := range []string{
+		`<li class="tree-dir">`,
+		`📁 pkg`,
+		`📁 deep`,
+		`<span class="file-coverage-badge coverage-fair" title="Regular">50%</span>`,
+		`<li class="file-item active" data-path="pkg/a/deep/two.go">`,
+		`<li class="tree-dir collapsed">`,
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML não contém %q", want)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package coverage
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlLine é uma linha não vazia do documento, sem indentação e sem
+// comentários
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// yamlParser lê o subconjunto de YAML usado em arquivos de configuração:
+// mapas e listas em bloco, listas em linha ([a, b]) e escalares simples ou
+// entre aspas. Âncoras, tags e strings de várias linhas (| e >) não são
+// suportadas.
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+// parseYAML converte o documento em valores compatíveis com encoding/json:
+// map[string]any, []any, string, float64, bool e nil
+func parseYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		text := stripYAMLComment(raw)
+		trimmed := strings.TrimLeft(text, " ")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("linha %d: tabulação na indentação", i+1)
+		}
+		if len(p.lines) == 0 && strings.TrimSpace(trimmed) == "---" {
+			continue
+		}
+		p.lines = append(p.lines, yamlLine{
+			number: i + 1,
+			indent: len(text) - len(trimmed),
+			text:   strings.TrimRight(trimmed, " \t"),
+		})
+	}
+
+	if len(p.lines) == 0 {
+		return map[string]any{}, nil
+	}
+	value, err := p.parseBlock(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("linha %d: indentação inesperada", p.lines[p.pos].number)
+	}
+	return value, nil
+}
+
+// parseBlock lê o mapa ou a lista que começa na linha atual
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	if isYAMLSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseMapping(indent int) (map[string]any, error) {
+	mapping := make(map[string]any)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("linha %d: indentação inesperada", line.number)
+		}
+		if isYAMLSequenceItem(line.text) {
+			return nil, fmt.Errorf("linha %d: item de lista dentro de um mapa", line.number)
+		}
+
+		key, rest, err := splitYAMLKey(line)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := mapping[key]; exists {
+			return nil, fmt.Errorf("linha %d: chave %q repetida", line.number, key)
+		}
+		p.pos++
+
+		var value any
+		switch {
+		case rest != "":
+			value, err = parseYAMLValue(rest, line.number)
+		case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+			value, err = p.parseBlock(p.lines[p.pos].indent)
+		case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isYAMLSequenceItem(p.lines[p.pos].text):
+			// Listas podem ter a mesma indentação da chave
+			value, err = p.parseSequence(indent)
+		}
+		if err != nil {
+			return nil, err
+		}
+		mapping[key] = value
+	}
+	return mapping, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) ([]any, error) {
+	sequence := []any{}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isYAMLSequenceItem(line.text) {
+			if line.indent > indent {
+				return nil, fmt.Errorf("linha %d: indentação inesperada", line.number)
+			}
+			break
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		var value any
+		var err error
+		switch {
+		case rest == "":
+			p.pos++
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				value, err = p.parseBlock(p.lines[p.pos].indent)
+			}
+		case isYAMLMappingEntry(rest):
+			// "- chave: valor" abre um mapa indentado na coluna da chave
+			p.lines[p.pos] = yamlLine{
+				number: line.number,
+				indent: indent + len(line.text) - len(rest),
+				text:   rest,
+			}
+			value, err = p.parseMapping(p.lines[p.pos].indent)
+		default:
+			p.pos++
+			value, err = parseYAMLValue(rest, line.number)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, value)
+	}
+	return sequence, nil
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func isYAMLMappingEntry(text string) bool {
+	_, _, err := splitYAMLKey(yamlLine{text: text})
+	return err == nil && text[0] != '[' && text[0] != '{'
+}
+
+// splitYAMLKey separa "chave: valor" na chave e no valor (vazio quando o
+// valor é um bloco nas linhas seguintes)
+func splitYAMLKey(line yamlLine) (string, string, error) {
+	text := line.text
+	end := -1
+	if text[0] == '"' || text[0] == '\'' {
+		if closing := strings.IndexByte(text[1:], text[0]); closing >= 0 {
+			end = closing + 2
+		}
+	} else {
+		end = strings.Index(text, ": ")
+		if end < 0 && strings.HasSuffix(text, ":") {
+			end = len(text) - 1
+		}
+	}
+	if end <= 0 || end >= len(text) || text[end] != ':' || (end+1 < len(text) && text[end+1] != ' ') {
+		return "", "", fmt.Errorf("linha %d: esperava \"chave: valor\"", line.number)
+	}
+
+	key, err := parseYAMLScalar(strings.TrimSpace(text[:end]), line.number)
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprint(key), strings.TrimSpace(text[end+1:]), nil
+}
+
+// parseYAMLValue lê um valor em linha: uma lista [a, b], um mapa vazio {}
+// ou um escalar
+func parseYAMLValue(text string, number int) (any, error) {
+	switch {
+	case text == "{}":
+		return map[string]any{}, nil
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("linha %d: lista sem ]", number)
+		}
+		items := []any{}
+		inner := strings.TrimSpace(text[1 : len(text)-1])
+		if inner == "" {
+			return items, nil
+		}
+		for _, item := range splitYAMLFlow(inner) {
+			value, err := parseYAMLScalar(strings.TrimSpace(item), number)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case strings.HasPrefix(text, "{"), strings.HasPrefix(text, "|"), strings.HasPrefix(text, ">"),
+		strings.HasPrefix(text, "&"), strings.HasPrefix(text, "*"), strings.HasPrefix(text, "!"):
+		return nil, fmt.Errorf("linha %d: sintaxe YAML não suportada: %s", number, text)
+	}
+	return parseYAMLScalar(text, number)
+}
+
+// splitYAMLFlow separa os itens de uma lista em linha, respeitando aspas
+func splitYAMLFlow(text string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case quote == '"' && c == '\\':
+			i++ // caractere escapado
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, text[start:])
+}
+
+var yamlNumber = regexp.MustCompile(`^[-+]?(\d+(\.\d*)?|\.\d+)([eE][-+]?\d+)?$`)
+
+// parseYAMLScalar interpreta um escalar: strings entre aspas, null, true,
+// false, números ou strings simples
+func parseYAMLScalar(text string, number int) (any, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: string inválida %s", number, text)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, fmt.Errorf("linha %d: string inválida %s", number, text)
+		}
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil, nil
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	}
+	if yamlNumber.MatchString(text) {
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: número inválido %s", number, text)
+		}
+		return value, nil
+	}
+	return text, nil
+}
+
+// stripYAMLComment remove um comentário (# no início ou após um espaço)
+// fora de aspas
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote == '"' && c == '\\':
+			i++ // caractere escapado
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			// Aspas só abrem uma string no início de um valor
+			if i == 0 || strings.ContainsRune(" [,:-", rune(line[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
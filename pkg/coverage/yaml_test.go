@@ -0,0 +1,95 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  any
+	}{
+		{"vazio", "# só comentários\n\n", map[string]any{}},
+		{
+			name:  "escalares",
+			input: "---\ntexto: olá mundo # comentário\nurl: http://example.com/#x\nnumero: 80\ndecimal: -1.5\nverdadeiro: true\nfalso: False\nnulo: ~\nvazio:\naspas: \"a # b\\n\"\nsimples: 'it''s'\n",
+			want: map[string]any{
+				"texto": "olá mundo", "url": "http://example.com/#x", "numero": 80.0, "decimal": -1.5,
+				"verdadeiro": true, "falso": false, "nulo": nil, "vazio": nil, "aspas": "a # b\n", "simples": "it's",
+			},
+		},
+		{
+			name:  "mapas aninhados",
+			input: "a:\n  b:\n    c: 1\n  d: 2\ne: 3\n",
+			want:  map[string]any{"a": map[string]any{"b": map[string]any{"c": 1.0}, "d": 2.0}, "e": 3.0},
+		},
+		{
+			name:  "listas",
+			input: "indentada:\n  - um\n  - \"dois\"\nmesma coluna:\n- 3\nem linha: [\"*.pb.go\", '**/vendor/**', 4]\nvazia: []\nmapa vazio: {}\n",
+			want: map[string]any{
+				"indentada":    []any{"um", "dois"},
+				"mesma coluna": []any{3.0},
+				"em linha":     []any{"*.pb.go", "**/vendor/**", 4.0},
+				"vazia":        []any{},
+				"mapa vazio":   map[string]any{},
+			},
+		},
+		{
+			name:  "lista de mapas",
+			input: "packages:\n  - pattern: a/**\n    min: 70\n  -\n    pattern: b\n  - c\n",
+			want: map[string]any{"packages": []any{
+				map[string]any{"pattern": "a/**", "min": 70.0},
+				map[string]any{"pattern": "b"},
+				"c",
+			}},
+		},
+		{"lista na raiz", "- a\n- b: 1\n", []any{"a", map[string]any{"b": 1.0}}},
+		{"CRLF", "a: 1\r\nb: 2\r\n", map[string]any{"a": 1.0, "b": 2.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("erro: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resultado = %#v\nesperado %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  string
+	}{
+		{"sem dois-pontos", "a: 1\nb\n", "linha 2"},
+		{"chave repetida", "a: 1\na: 2\n", "linha 2"},
+		{"indentação inesperada", "a: 1\n  b: 2\n", "linha 2"},
+		{"tabulação", "a:\n\tb: 1\n", "linha 2"},
+		{"lista dentro de mapa", "a: 1\n- b\n", "linha 2"},
+		{"bloco de texto", "a: |\n  texto\n", "linha 1"},
+		{"âncora", "a: &x 1\n", "linha 1"},
+		{"lista sem fechamento", "a: [1, 2\n", "linha 1"},
+		{"aspas sem fechamento", "a: \"texto\n", "linha 1"},
+		{"item mal indentado", "a:\n  - 1\n    - 2\n", "linha 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseYAML([]byte(tt.input))
+			if err == nil {
+				t.Fatal("esperava erro")
+			}
+			if !strings.Contains(err.Error(), tt.line) {
+				t.Errorf("erro = %v, esperado em %s", err, tt.line)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ set -e
 GREEN='\033[0;32m'
 BLUE='\033[0;34m'
 YELLOW='\033[1;33m'
+RED='\033[0;31m'
 NC='\033[0m' # No Color
 
 echo -e "${BLUE}📊 Script de Geração de Relatório de Cobertura${NC}\n"
@@ -77,7 +78,7 @@ echo ""
 
 # Passo 3: Gerar relatório HTML
 echo -e "${YELLOW}3️⃣  Gerando relatório HTML interativo...${NC}"
-go run cmd/coverage-report/main.go -in "$COVERAGE" -out "$OUTPUT"
+go run ./cmd/coverage-report -in "$COVERAGE" -out "$OUTPUT"
 
 echo ""
 echo -e "${GREEN}✨ Relatório gerado com sucesso!${NC}"